@@ -0,0 +1,166 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"strings"
+)
+
+//AdjacencyRecord is a node flattened into an (id, parent id, value) record
+type AdjacencyRecord[K comparable] struct {
+	ID K
+	//ParentID is the id of the parent of the node. It is only meaningful if HasParent is true
+	ParentID  K
+	HasParent bool
+	Value     interface{}
+}
+
+//AdjacencyListError reports the records that could not be placed in a tree
+type AdjacencyListError[K comparable] struct {
+	//Orphans are the ids of records whose parent id does not match any record
+	Orphans []K
+	//Duplicates are the ids shared by more than one record. Only the first record with an id is used
+	Duplicates []K
+	//Cycles are the ids of records that are their own ancestor
+	Cycles []K
+}
+
+func (e *AdjacencyListError[K]) Error() string {
+	problems := make([]string, 0, 3)
+	if len(e.Orphans) > 0 {
+		problems = append(problems, fmt.Sprintf("%v: %v", ErrOrphan, e.Orphans))
+	}
+	if len(e.Duplicates) > 0 {
+		problems = append(problems, fmt.Sprintf("%v: %v", ErrDuplicateID, e.Duplicates))
+	}
+	if len(e.Cycles) > 0 {
+		problems = append(problems, fmt.Sprintf("%v: %v", ErrCycle, e.Cycles))
+	}
+	return strings.Join(problems, "; ")
+}
+
+//Is allows errors.Is to match ErrOrphan, ErrDuplicateID and ErrCycle against the problems found
+func (e *AdjacencyListError[K]) Is(target error) bool {
+	switch target {
+	case ErrOrphan:
+		return len(e.Orphans) > 0
+	case ErrDuplicateID:
+		return len(e.Duplicates) > 0
+	case ErrCycle:
+		return len(e.Cycles) > 0
+	}
+	return false
+}
+
+//BuildFromAdjacencyList builds trees from flat records, each of which becomes the value of a node.
+//idFn returns the id of a record and parentIDFn returns the id of its parent, or false if the record is a root.
+//Children are added in the order of the records. The roots are returned in the order of the records.
+//If any records are orphans, have duplicate ids or form cycles, an *AdjacencyListError is returned along with
+//the roots that could be built. Those records, and their descendants, are not part of any returned tree.
+func BuildFromAdjacencyList[R any, K comparable](records []R, idFn func(R) K, parentIDFn func(R) (K, bool)) ([]NodeIFace, error) {
+	problems := &AdjacencyListError[K]{}
+	nodes := make(map[K]NodeIFace, len(records))
+	ids := make([]K, 0, len(records))
+	parents := make(map[K]K, len(records))
+	isRoot := make(map[K]bool)
+	for _, r := range records {
+		id := idFn(r)
+		if _, exists := nodes[id]; exists {
+			problems.Duplicates = append(problems.Duplicates, id)
+			continue
+		}
+		nodes[id] = NewNode(r, nil)
+		ids = append(ids, id)
+		if pid, ok := parentIDFn(r); ok {
+			parents[id] = pid
+		} else {
+			isRoot[id] = true
+		}
+	}
+
+	//chase parent ids to find orphans and records that are their own ancestor
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[K]int, len(ids))
+	isOrphan := make(map[K]bool)
+	inCycle := make(map[K]bool)
+	for _, id := range ids {
+		path := make([]K, 0)
+		for cur := id; ; {
+			if state[cur] == visited {
+				break
+			}
+			if state[cur] == visiting {
+				for i := len(path) - 1; i >= 0; i-- {
+					inCycle[path[i]] = true
+					if path[i] == cur {
+						break
+					}
+				}
+				break
+			}
+			state[cur] = visiting
+			path = append(path, cur)
+			if isRoot[cur] {
+				break
+			}
+			pid := parents[cur]
+			if _, ok := nodes[pid]; !ok {
+				isOrphan[cur] = true
+				break
+			}
+			cur = pid
+		}
+		for _, p := range path {
+			state[p] = visited
+		}
+	}
+
+	roots := make([]NodeIFace, 0)
+	for _, id := range ids {
+		switch {
+		case isRoot[id]:
+			roots = append(roots, nodes[id])
+		case isOrphan[id]:
+			problems.Orphans = append(problems.Orphans, id)
+		case inCycle[id]:
+			problems.Cycles = append(problems.Cycles, id)
+		default:
+			nodes[parents[id]].AddChild(nodes[id])
+		}
+	}
+	if len(problems.Orphans) > 0 || len(problems.Duplicates) > 0 || len(problems.Cycles) > 0 {
+		return roots, problems
+	}
+	return roots, nil
+}
+
+//ToAdjacencyList flattens the tree rooted at root into records in pre-order, using idFn to identify nodes.
+//root is always flattened as a record without a parent
+func ToAdjacencyList[K comparable](root NodeIFace, idFn func(NodeIFace) K) []AdjacencyRecord[K] {
+	records := make([]AdjacencyRecord[K], 0)
+	//path holds the ids of the nodes from the root to the parent of the node being flattened
+	path := make([]K, 0)
+	Walk(root, func(n NodeIFace, depth int) WalkAction {
+		record := AdjacencyRecord[K]{ID: idFn(n), Value: n.GetValue()}
+		path = path[:depth]
+		if depth > 0 {
+			record.ParentID = path[depth-1]
+			record.HasParent = true
+		}
+		path = append(path, record.ID)
+		records = append(records, record)
+		return WalkContinue
+	})
+	return records
+}
@@ -0,0 +1,137 @@
+package tree_test
+
+import (
+	"errors"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type accountRow struct {
+	id       int
+	parentID int
+	name     string
+}
+
+func rowID(r accountRow) int {
+	return r.id
+}
+
+func rowParentID(r accountRow) (int, bool) {
+	return r.parentID, r.parentID != 0
+}
+
+func rowName(n tree.NodeIFace) interface{} {
+	return n.GetValue().(accountRow).name
+}
+
+func TestBuildFromAdjacencyList(t *testing.T) {
+	rows := []accountRow{
+		{3, 1, "bank"},
+		{1, 0, "assets"},
+		{4, 2, "loan"},
+		{2, 0, "liabilities"},
+		{5, 1, "cash"},
+		{6, 3, "savings"},
+	}
+	roots, err := tree.BuildFromAdjacencyList(rows, rowID, rowParentID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(roots))
+
+	assets := roots[0]
+	assert.Equal(t, "assets", rowName(assets))
+	assert.Equal(t, 2, len(assets.GetChildren()))
+	assert.Equal(t, "bank", rowName(assets.GetChildAt(0)))
+	assert.Equal(t, "cash", rowName(assets.GetChildAt(1)))
+	assert.Equal(t, "savings", rowName(assets.GetChildAt(0).GetChildAt(0)))
+
+	liabilities := roots[1]
+	assert.Equal(t, "liabilities", rowName(liabilities))
+	assert.Equal(t, "loan", rowName(liabilities.GetChildAt(0)))
+	assert.Nil(t, tree.Validate(assets))
+	assert.Nil(t, tree.Validate(liabilities))
+}
+
+func TestBuildFromAdjacencyList_ReportsProblems(t *testing.T) {
+	rows := []accountRow{
+		{1, 0, "assets"},
+		{2, 1, "bank"},
+		{2, 1, "duplicate bank"},
+		{3, 99, "orphan"},
+		{4, 3, "child of orphan"},
+		{5, 6, "cycle a"},
+		{6, 5, "cycle b"},
+		{7, 7, "own parent"},
+	}
+	roots, err := tree.BuildFromAdjacencyList(rows, rowID, rowParentID)
+	assert.Equal(t, 1, len(roots))
+	assert.Equal(t, 2, roots[0].GetSize())
+	assert.Equal(t, "bank", rowName(roots[0].GetChildAt(0)))
+
+	var listErr *tree.AdjacencyListError[int]
+	assert.True(t, errors.As(err, &listErr))
+	assert.Equal(t, []int{3}, listErr.Orphans)
+	assert.Equal(t, []int{2}, listErr.Duplicates)
+	assert.Equal(t, []int{5, 6, 7}, listErr.Cycles)
+	assert.ErrorIs(t, err, tree.ErrOrphan)
+	assert.ErrorIs(t, err, tree.ErrDuplicateID)
+	assert.ErrorIs(t, err, tree.ErrCycle)
+	assert.Equal(t, "tree: parent not found: [3]; tree: duplicate id: [2]; tree: operation would create a cycle: [5 6 7]", err.Error())
+}
+
+func TestBuildFromAdjacencyList_ErrorOnlyMatchesProblemsFound(t *testing.T) {
+	rows := []accountRow{{1, 0, "assets"}, {2, 3, "orphan"}}
+	_, err := tree.BuildFromAdjacencyList(rows, rowID, rowParentID)
+	assert.ErrorIs(t, err, tree.ErrOrphan)
+	assert.False(t, errors.Is(err, tree.ErrDuplicateID))
+	assert.False(t, errors.Is(err, tree.ErrCycle))
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestToAdjacencyList(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	id := func(n tree.NodeIFace) string {
+		return n.GetValue().(string)
+	}
+	expected := []tree.AdjacencyRecord[string]{
+		{ID: "root", Value: "root"},
+		{ID: "a", ParentID: "root", HasParent: true, Value: "a"},
+		{ID: "d", ParentID: "a", HasParent: true, Value: "d"},
+		{ID: "e", ParentID: "a", HasParent: true, Value: "e"},
+		{ID: "b", ParentID: "root", HasParent: true, Value: "b"},
+		{ID: "f", ParentID: "b", HasParent: true, Value: "f"},
+		{ID: "c", ParentID: "root", HasParent: true, Value: "c"},
+	}
+	assert.Equal(t, expected, tree.ToAdjacencyList(root, id))
+
+	//a subtree is flattened with its root as a root record
+	sub := tree.ToAdjacencyList(root.GetChildAt(0), id)
+	assert.Equal(t, 3, len(sub))
+	assert.Equal(t, tree.AdjacencyRecord[string]{ID: "a", Value: "a"}, sub[0])
+}
+
+func TestAdjacencyList_RoundTrip(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	records := tree.ToAdjacencyList(root, func(n tree.NodeIFace) string {
+		return n.GetValue().(string)
+	})
+	roots, err := tree.BuildFromAdjacencyList(records,
+		func(r tree.AdjacencyRecord[string]) string { return r.ID },
+		func(r tree.AdjacencyRecord[string]) (string, bool) { return r.ParentID, r.HasParent },
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(roots))
+	actual := tree.ToAdjacencyList(roots[0], func(n tree.NodeIFace) string {
+		return n.GetValue().(tree.AdjacencyRecord[string]).ID
+	})
+	for i := range actual {
+		actual[i].Value = actual[i].Value.(tree.AdjacencyRecord[string]).Value
+	}
+	assert.Equal(t, records, actual)
+}
@@ -0,0 +1,202 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "fmt"
+
+//Number is the constraint for the values rolled up by SumOf, MinOf and MaxOf
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+//AggregateFunc returns the result for node n given the results for its children, in order
+type AggregateFunc[R any] func(n NodeIFace, children []R) R
+
+//AggregateVisitor rolls results up a tree in post-order, so that the result for each node is worked out from the
+//node and the results for its children, e.g. the balance of an account and all of its sub accounts.
+//
+//Results are remembered, so asking again for a node whose subtree has not changed costs nothing. The visitor does
+//not know when a tree changes, so call Invalidate with each changed node, or Reset, before asking again. For a tree
+//of Nodes call Watch instead, and the visitor forgets the results that each change makes stale until it is closed.
+//
+//Visit and Result do not change the tree, so they may run while other goroutines read it, e.g. under the read lock
+//of a ConcurrentTree. An AggregateVisitor must not itself be used by more than one goroutine at a time.
+type AggregateVisitor[R any] struct {
+	VisitorIFace
+	fn   AggregateFunc[R]
+	memo map[NodeIFace]R
+	//watched are the Nodes that the visitor listens to
+	watched map[*Node]bool
+}
+
+//NewAggregateVisitor returns an AggregateVisitor that works out the result for each node with fn
+func NewAggregateVisitor[R any](fn AggregateFunc[R]) *AggregateVisitor[R] {
+	return &AggregateVisitor[R]{fn: fn, memo: make(map[NodeIFace]R), watched: make(map[*Node]bool)}
+}
+
+//Watch listens for changes to the tree rooted at n, forgetting the results that each change makes stale, until
+//the visitor is closed. It returns an error wrapping ErrUnsupportedNode if n is not a *Node. Watch and Close
+//change the tree, so for a ConcurrentTree call them within Update with the root it is given
+func (v *AggregateVisitor[R]) Watch(n NodeIFace) error {
+	nn, ok := n.(*Node)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedNode, n)
+	}
+	if !v.watched[nn] {
+		v.watched[nn] = true
+		nn.observe(v)
+	}
+	return nil
+}
+
+//Close stops the visitor listening to the trees it watches, and forgets all results
+func (v *AggregateVisitor[R]) Close() {
+	for n := range v.watched {
+		n.unobserve(v)
+	}
+	v.watched = make(map[*Node]bool)
+	v.Reset()
+}
+
+//Visit returns a map[NodeIFace]R holding the result for each node of the tree rooted at n
+func (v *AggregateVisitor[R]) Visit(n NodeIFace) interface{} {
+	v.Result(n)
+	results := make(map[NodeIFace]R)
+	Walk(n, func(node NodeIFace, _ int) WalkAction {
+		results[node] = v.memo[node]
+		return WalkContinue
+	})
+	return results
+}
+
+//Result returns the result for n, working out the results for any of its descendants that are not remembered
+func (v *AggregateVisitor[R]) Result(n NodeIFace) R {
+	if r, ok := v.memo[n]; ok {
+		return r
+	}
+	//in reverse pre-order every node comes after all of its descendants
+	pending := make([]NodeIFace, 0)
+	Walk(n, func(node NodeIFace, _ int) WalkAction {
+		if _, ok := v.memo[node]; ok {
+			return WalkSkipChildren
+		}
+		pending = append(pending, node)
+		return WalkContinue
+	})
+	for i := len(pending) - 1; i >= 0; i-- {
+		node := pending[i]
+		children := node.GetChildren()
+		results := make([]R, len(children))
+		for j, c := range children {
+			results[j] = v.memo[c]
+		}
+		v.memo[node] = v.fn(node, results)
+	}
+	return v.memo[n]
+}
+
+//Invalidate forgets the results for n and its ancestors, which depend on it. Call it after changing the value or
+//children of n. To forget a removed subtree, invalidate its old parent
+func (v *AggregateVisitor[R]) Invalidate(n NodeIFace) {
+	for p := n; p != nil; p = p.GetParent() {
+		delete(v.memo, p)
+	}
+}
+
+//Reset forgets all results
+func (v *AggregateVisitor[R]) Reset() {
+	v.memo = make(map[NodeIFace]R)
+}
+
+//forget forgets the results for the subtree rooted at n, which may have left the trees the visitor watches
+func (v *AggregateVisitor[R]) forget(n NodeIFace) {
+	Walk(n, func(node NodeIFace, _ int) WalkAction {
+		delete(v.memo, node)
+		return WalkContinue
+	})
+}
+
+func (v *AggregateVisitor[R]) checkAdd(_, _ NodeIFace) error {
+	return nil
+}
+
+//notify forgets the results made stale by a change
+func (v *AggregateVisitor[R]) notify(e Event) {
+	switch e := e.(type) {
+	case ChildRemoved:
+		v.forget(e.Child)
+	case ChildMoved:
+		if e.OldParent != e.Target {
+			if e.OldParent != nil {
+				v.Invalidate(e.OldParent)
+			}
+			v.forget(e.Child)
+		}
+	case ChildrenReplaced:
+		for _, c := range e.OldChildren {
+			v.forget(c)
+		}
+	case ParentChanged:
+		if e.OldParent != nil {
+			v.Invalidate(e.OldParent)
+		}
+		v.forget(e.Target)
+	}
+	v.Invalidate(e.GetTarget())
+}
+
+//SumOf returns an AggregateFunc that adds the value of a node to the results for its children
+func SumOf[N Number](value func(n NodeIFace) N) AggregateFunc[N] {
+	return func(n NodeIFace, children []N) N {
+		sum := value(n)
+		for _, c := range children {
+			sum += c
+		}
+		return sum
+	}
+}
+
+//MinOf returns an AggregateFunc that returns the least of the value of a node and the results for its children
+func MinOf[N Number](value func(n NodeIFace) N) AggregateFunc[N] {
+	return func(n NodeIFace, children []N) N {
+		m := value(n)
+		for _, c := range children {
+			if c < m {
+				m = c
+			}
+		}
+		return m
+	}
+}
+
+//MaxOf returns an AggregateFunc that returns the greatest of the value of a node and the results for its children
+func MaxOf[N Number](value func(n NodeIFace) N) AggregateFunc[N] {
+	return func(n NodeIFace, children []N) N {
+		m := value(n)
+		for _, c := range children {
+			if c > m {
+				m = c
+			}
+		}
+		return m
+	}
+}
+
+//CountNodes returns an AggregateFunc that counts the nodes of each subtree
+func CountNodes() AggregateFunc[int] {
+	return func(_ NodeIFace, children []int) int {
+		count := 1
+		for _, c := range children {
+			count += c
+		}
+		return count
+	}
+}
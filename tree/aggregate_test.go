@@ -0,0 +1,170 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestAggregateVisitor_ImplementsVisitorInterface(t *testing.T) {
+	sut := tree.NewAggregateVisitor(tree.CountNodes())
+	_, ok := interface{}(sut).(tree.VisitorIFace)
+	assert.True(t, ok)
+}
+
+/**
+ *      assets(0)
+ *      /       \
+ *  bank(100)  loans(0)
+ *    |         /   \
+ *  cash(5)   l1(-20) l2(7)
+ */
+func buildBalances() (root, bank, loans, cash, l1, l2 tree.NodeIFace) {
+	root = tree.NewNode(0, nil)
+	bank = tree.NewNode(100, nil)
+	loans = tree.NewNode(0, nil)
+	cash = tree.NewNode(5, nil)
+	l1 = tree.NewNode(-20, nil)
+	l2 = tree.NewNode(7, nil)
+	root.AddChild(bank).AddChild(loans)
+	bank.AddChild(cash)
+	loans.AddChild(l1).AddChild(l2)
+	return
+}
+
+func balance(n tree.NodeIFace) int {
+	return n.GetValue().(int)
+}
+
+func TestAggregateVisitor_Reducers(t *testing.T) {
+	root, bank, loans, cash, l1, l2 := buildBalances()
+
+	sum := root.Accept(tree.NewAggregateVisitor(tree.SumOf(balance))).(map[tree.NodeIFace]int)
+	assert.Equal(t, map[tree.NodeIFace]int{root: 92, bank: 105, loans: -13, cash: 5, l1: -20, l2: 7}, sum)
+
+	min := root.Accept(tree.NewAggregateVisitor(tree.MinOf(balance))).(map[tree.NodeIFace]int)
+	assert.Equal(t, -20, min[root])
+	assert.Equal(t, 5, min[bank])
+
+	max := root.Accept(tree.NewAggregateVisitor(tree.MaxOf(func(n tree.NodeIFace) float64 {
+		return float64(balance(n))
+	}))).(map[tree.NodeIFace]float64)
+	assert.Equal(t, 100.0, max[root])
+	assert.Equal(t, 7.0, max[loans])
+
+	count := bank.Accept(tree.NewAggregateVisitor(tree.CountNodes())).(map[tree.NodeIFace]int)
+	assert.Equal(t, map[tree.NodeIFace]int{bank: 2, cash: 1}, count)
+}
+
+func TestAggregateVisitor_CustomReducer(t *testing.T) {
+	root, _, _, _, _, _ := buildBalances()
+	paths := tree.NewAggregateVisitor(func(n tree.NodeIFace, children []string) string {
+		s := tree.FormatValue(n)
+		for _, c := range children {
+			s += "(" + c + ")"
+		}
+		return s
+	})
+	assert.Equal(t, "0(100(5))(0(-20)(7))", paths.Result(root))
+}
+
+func TestAggregateVisitor_MemoisesUntilInvalidated(t *testing.T) {
+	root, bank, loans, cash, _, l2 := buildBalances()
+	calls := 0
+	sum := tree.SumOf(balance)
+	sut := tree.NewAggregateVisitor(func(n tree.NodeIFace, children []int) int {
+		calls++
+		return sum(n, children)
+	})
+
+	assert.Equal(t, 105, sut.Result(bank))
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 92, sut.Result(root))
+	assert.Equal(t, 6, calls, "bank subtree is not worked out again")
+	sut.Visit(root)
+	assert.Equal(t, 6, calls)
+
+	l2.SetValue(10)
+	sut.Invalidate(l2)
+	assert.Equal(t, 95, sut.Result(root))
+	assert.Equal(t, 9, calls, "only l2, loans and root are worked out again")
+	assert.Equal(t, 105, sut.Result(bank))
+	assert.Equal(t, 9, calls)
+
+	bank.RemoveChild(cash)
+	sut.Invalidate(bank)
+	assert.Equal(t, 90, sut.Result(root))
+	assert.Equal(t, -10, sut.Visit(root).(map[tree.NodeIFace]int)[loans])
+
+	sut.Reset()
+	calls = 0
+	assert.Equal(t, 90, sut.Result(root))
+	assert.Equal(t, 5, calls)
+}
+
+func TestAggregateVisitor_WatchForgetsStaleResults(t *testing.T) {
+	root, bank, loans, cash, l1, l2 := buildBalances()
+	calls := 0
+	sum := tree.SumOf(balance)
+	sut := tree.NewAggregateVisitor(func(n tree.NodeIFace, children []int) int {
+		calls++
+		return sum(n, children)
+	})
+	assert.NoError(t, sut.Watch(root))
+	defer sut.Close()
+
+	assert.Equal(t, 92, sut.Result(root))
+	l2.SetValue(10)
+	assert.Equal(t, 95, sut.Result(root))
+	assert.Equal(t, 9, calls, "only l2, loans and root are worked out again")
+
+	//cash leaves the tree, so a change to it is not seen, and it is worked out again when asked for
+	bank.RemoveChild(cash)
+	assert.Equal(t, 90, sut.Result(root))
+	cash.SetValue(1)
+	assert.Equal(t, 1, sut.Result(cash))
+
+	assert.NoError(t, l1.MoveTo(bank, 0))
+	assert.Equal(t, 80, sut.Result(bank))
+	assert.Equal(t, 10, sut.Result(loans))
+	assert.Equal(t, 90, sut.Result(root))
+
+	loans.SetChildren(cash)
+	assert.Equal(t, 81, sut.Result(root))
+	bank.AddChild(l2)
+	assert.Equal(t, 90, sut.Result(bank))
+	assert.Equal(t, 91, sut.Result(root))
+
+	//changes below the watched node are still seen once it is added to another tree
+	top := tree.NewNode(1000, nil)
+	top.AddChild(root)
+	assert.Equal(t, 1091, sut.Result(top))
+	cash.SetValue(2)
+	assert.Equal(t, 1092, sut.Result(top))
+
+	sut.Close()
+	calls = 0
+	cash.SetValue(3)
+	assert.Equal(t, 1093, sut.Result(top))
+	assert.Equal(t, 7, calls)
+	cash.SetValue(4)
+	assert.Equal(t, 1093, sut.Result(top), "a closed visitor no longer watches")
+
+	assert.ErrorIs(t, sut.Watch(tree.ToPersistent(root)), tree.ErrUnsupportedNode)
+}
+
+func TestAggregateVisitor_ResultDoesNotChangeTheTree(t *testing.T) {
+	root, _, _, cash, _, _ := buildBalances()
+	sut := tree.NewAggregateVisitor(tree.SumOf(balance))
+	assert.Equal(t, 92, sut.Result(root))
+	cash.SetValue(6)
+	assert.Equal(t, 92, sut.Result(root), "an unwatched tree is not listened to")
+	sut.Invalidate(cash)
+	assert.Equal(t, 93, sut.Result(root))
+}
+
+func TestAggregateVisitor_DeepChain(t *testing.T) {
+	root, _ := buildChain(100000)
+	sut := tree.NewAggregateVisitor(tree.CountNodes())
+	assert.Equal(t, 100000, sut.Result(root))
+}
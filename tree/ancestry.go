@@ -0,0 +1,180 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+//IsAncestorOf returns true if a is the parent, grandparent etc. of n. A node is not its own ancestor
+func IsAncestorOf(a, n NodeIFace) bool {
+	if a == nil || n == nil {
+		return false
+	}
+	return isAncestor(a, n)
+}
+
+//IsDescendantOf returns true if n is a child, grandchild etc. of a. A node is not its own descendant
+func IsDescendantOf(n, a NodeIFace) bool {
+	return IsAncestorOf(a, n)
+}
+
+//LowestCommonAncestor returns the deepest node that is an ancestor of, or the same node as, a and each of others.
+//It returns nil if the nodes are not all in the same tree, or if any of them is nil
+func LowestCommonAncestor(a NodeIFace, others ...NodeIFace) NodeIFace {
+	if a == nil {
+		return nil
+	}
+	common := a.GetAncestorsAndSelf()
+	for _, b := range others {
+		if b == nil {
+			return nil
+		}
+		path := b.GetAncestorsAndSelf()
+		i := 0
+		for i < len(common) && i < len(path) && common[i] == path[i] {
+			i++
+		}
+		if i == 0 {
+			return nil
+		}
+		common = common[:i]
+	}
+	return common[len(common)-1]
+}
+
+//PathBetween returns the nodes on the path from a up to their lowest common ancestor and down to b, including
+//a and b. It returns nil if a and b are not in the same tree
+func PathBetween(a, b NodeIFace) []NodeIFace {
+	lca := LowestCommonAncestor(a, b)
+	if lca == nil {
+		return nil
+	}
+	path := make([]NodeIFace, 0)
+	for n := a; n != lca; n = n.GetParent() {
+		path = append(path, n)
+	}
+	path = append(path, lca)
+	down := make([]NodeIFace, 0)
+	for n := b; n != lca; n = n.GetParent() {
+		down = append(down, n)
+	}
+	for i := len(down) - 1; i >= 0; i-- {
+		path = append(path, down[i])
+	}
+	return path
+}
+
+//Distance returns the number of edges on the path between a and b, or -1 if they are not in the same tree
+func Distance(a, b NodeIFace) int {
+	lca := LowestCommonAncestor(a, b)
+	if lca == nil {
+		return -1
+	}
+	return a.GetDepth() + b.GetDepth() - 2*lca.GetDepth()
+}
+
+//LCAIndex answers lowest common ancestor queries for a tree in O(log n) time using binary lifting, after
+//O(n log n) preprocessing. The index is a snapshot, so it must be rebuilt if the tree is changed
+type LCAIndex struct {
+	//index maps each node to its position in nodes
+	index map[NodeIFace]int
+	nodes []NodeIFace
+	depth []int
+	//up[k][i] is the position of the 2^k th ancestor of node i, or the root if there is none
+	up [][]int
+}
+
+//NewLCAIndex returns an LCAIndex for the tree rooted at root
+func NewLCAIndex(root NodeIFace) *LCAIndex {
+	x := &LCAIndex{index: make(map[NodeIFace]int)}
+	parents := make([]int, 0)
+	Walk(root, func(n NodeIFace, depth int) WalkAction {
+		parent := 0
+		if depth > 0 {
+			parent = x.index[n.GetParent()]
+		}
+		x.index[n] = len(x.nodes)
+		x.nodes = append(x.nodes, n)
+		x.depth = append(x.depth, depth)
+		parents = append(parents, parent)
+		return WalkContinue
+	})
+	x.up = [][]int{parents}
+	for k := 1; 1<<k < len(x.nodes); k++ {
+		prev := x.up[k-1]
+		level := make([]int, len(x.nodes))
+		for i := range level {
+			level[i] = prev[prev[i]]
+		}
+		x.up = append(x.up, level)
+	}
+	return x
+}
+
+//Contains returns true if n was in the tree when the index was built
+func (x *LCAIndex) Contains(n NodeIFace) bool {
+	_, ok := x.index[n]
+	return ok
+}
+
+//Depth returns the depth of n below the indexed root, or -1 if n is not in the index
+func (x *LCAIndex) Depth(n NodeIFace) int {
+	i, ok := x.index[n]
+	if !ok {
+		return -1
+	}
+	return x.depth[i]
+}
+
+//LowestCommonAncestor returns the deepest node that is an ancestor of, or the same node as, both a and b,
+//or nil if either is not in the index
+func (x *LCAIndex) LowestCommonAncestor(a, b NodeIFace) NodeIFace {
+	i, ok := x.index[a]
+	if !ok {
+		return nil
+	}
+	j, ok := x.index[b]
+	if !ok {
+		return nil
+	}
+	return x.nodes[x.lca(i, j)]
+}
+
+//Distance returns the number of edges on the path between a and b, or -1 if either is not in the index
+func (x *LCAIndex) Distance(a, b NodeIFace) int {
+	i, ok := x.index[a]
+	if !ok {
+		return -1
+	}
+	j, ok := x.index[b]
+	if !ok {
+		return -1
+	}
+	return x.depth[i] + x.depth[j] - 2*x.depth[x.lca(i, j)]
+}
+
+//lca returns the position of the lowest common ancestor of the nodes at positions i and j
+func (x *LCAIndex) lca(i, j int) int {
+	if x.depth[i] < x.depth[j] {
+		i, j = j, i
+	}
+	//lift i to the depth of j
+	for k, diff := 0, x.depth[i]-x.depth[j]; diff > 0; k, diff = k+1, diff>>1 {
+		if diff&1 == 1 {
+			i = x.up[k][i]
+		}
+	}
+	if i == j {
+		return i
+	}
+	//lift both to just below their lowest common ancestor
+	for k := len(x.up) - 1; k >= 0; k-- {
+		if x.up[k][i] != x.up[k][j] {
+			i, j = x.up[k][i], x.up[k][j]
+		}
+	}
+	return x.up[0][i]
+}
@@ -0,0 +1,107 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestIsAncestorOf(t *testing.T) {
+	root, a, b, _, d, _, _ := buildWalkTree()
+	assert.True(t, tree.IsAncestorOf(root, d))
+	assert.True(t, tree.IsAncestorOf(a, d))
+	assert.False(t, tree.IsAncestorOf(b, d))
+	assert.False(t, tree.IsAncestorOf(d, d))
+	assert.False(t, tree.IsAncestorOf(d, root))
+	assert.False(t, tree.IsAncestorOf(nil, d))
+	assert.True(t, tree.IsDescendantOf(d, root))
+	assert.False(t, tree.IsDescendantOf(root, d))
+}
+
+func TestLowestCommonAncestor(t *testing.T) {
+	root, a, b, c, d, e, f := buildWalkTree()
+	assert.Equal(t, a, tree.LowestCommonAncestor(d, e))
+	assert.Equal(t, root, tree.LowestCommonAncestor(d, f))
+	assert.Equal(t, root, tree.LowestCommonAncestor(d, e, c))
+	assert.Equal(t, a, tree.LowestCommonAncestor(a, d))
+	assert.Equal(t, b, tree.LowestCommonAncestor(f, b))
+	assert.Equal(t, d, tree.LowestCommonAncestor(d))
+	assert.Equal(t, root, tree.LowestCommonAncestor(root, f))
+	assert.Nil(t, tree.LowestCommonAncestor(d, tree.NewNode("x", nil)))
+	assert.Nil(t, tree.LowestCommonAncestor(d, nil))
+	assert.Nil(t, tree.LowestCommonAncestor(nil, d))
+}
+
+func TestPathBetween(t *testing.T) {
+	root, a, b, _, d, e, f := buildWalkTree()
+	assert.Equal(t, []tree.NodeIFace{d, a, e}, tree.PathBetween(d, e))
+	assert.Equal(t, []tree.NodeIFace{d, a, root, b, f}, tree.PathBetween(d, f))
+	assert.Equal(t, []tree.NodeIFace{root, b, f}, tree.PathBetween(root, f))
+	assert.Equal(t, []tree.NodeIFace{f, b}, tree.PathBetween(f, b))
+	assert.Equal(t, []tree.NodeIFace{d}, tree.PathBetween(d, d))
+	assert.Nil(t, tree.PathBetween(d, tree.NewNode("x", nil)))
+}
+
+func TestDistance(t *testing.T) {
+	root, a, _, c, d, e, f := buildWalkTree()
+	assert.Equal(t, 2, tree.Distance(d, e))
+	assert.Equal(t, 4, tree.Distance(d, f))
+	assert.Equal(t, 1, tree.Distance(a, root))
+	assert.Equal(t, 0, tree.Distance(c, c))
+	assert.Equal(t, -1, tree.Distance(c, tree.NewNode("x", nil)))
+}
+
+func TestLCAIndex_MatchesLowestCommonAncestor(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	nodes := []tree.NodeIFace{tree.NewNode(0, nil)}
+	for i := 1; i < 500; i++ {
+		n := tree.NewNode(i, nil)
+		//bias towards recent nodes to give deep branches
+		parent := nodes[len(nodes)-1-rnd.Intn(len(nodes)/4+1)]
+		parent.AddChild(n)
+		nodes = append(nodes, n)
+	}
+	sut := tree.NewLCAIndex(nodes[0])
+	for i := 0; i < 2000; i++ {
+		a, b := nodes[rnd.Intn(len(nodes))], nodes[rnd.Intn(len(nodes))]
+		assert.Equal(t, tree.LowestCommonAncestor(a, b), sut.LowestCommonAncestor(a, b))
+		assert.Equal(t, tree.Distance(a, b), sut.Distance(a, b))
+	}
+	assert.Equal(t, nodes[42].GetDepth(), sut.Depth(nodes[42]))
+}
+
+func TestLCAIndex_NodesNotInIndex(t *testing.T) {
+	root, a, _, _, d, e, _ := buildWalkTree()
+	sut := tree.NewLCAIndex(a)
+	x := tree.NewNode("x", nil)
+	assert.True(t, sut.Contains(d))
+	assert.False(t, sut.Contains(root))
+	assert.Equal(t, a, sut.LowestCommonAncestor(d, e))
+	assert.Equal(t, 1, sut.Depth(e))
+	assert.Nil(t, sut.LowestCommonAncestor(d, x))
+	assert.Nil(t, sut.LowestCommonAncestor(x, d))
+	assert.Equal(t, -1, sut.Distance(d, x))
+	assert.Equal(t, -1, sut.Depth(x))
+
+	single := tree.NewLCAIndex(x)
+	assert.Equal(t, x, single.LowestCommonAncestor(x, x))
+}
+
+func TestLCAIndex_DeepChain(t *testing.T) {
+	root, leaf := buildChain(100000)
+	sut := tree.NewLCAIndex(root)
+	mid := leaf
+	for i := 0; i < 40000; i++ {
+		mid = mid.GetParent()
+	}
+	assert.Equal(t, mid, sut.LowestCommonAncestor(leaf, mid))
+	assert.Equal(t, 40000, sut.Distance(mid, leaf))
+}
@@ -0,0 +1,134 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "reflect"
+
+//ValueCopier returns the value to give the copy of a node with value v
+type ValueCopier func(v interface{}) interface{}
+
+//ShallowValueCopier returns v as is, so a node and its copy share any pointers, slices or maps in its value
+func ShallowValueCopier(v interface{}) interface{} {
+	return v
+}
+
+//DeepValueCopier returns a deep copy of v. Pointers, slices, maps, arrays, interfaces and the exported fields
+//of structs are copied, with shared and circular pointers copied once. Unexported struct fields, channels and
+//functions cannot be copied using reflection, so they are shared with v
+func DeepValueCopier(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(v), make(map[copiedPtr]reflect.Value)).Interface()
+}
+
+//copiedPtr identifies a pointer that has already been copied
+type copiedPtr struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+//deepCopy returns a copy of v. copied maps each pointer already copied to its copy
+func deepCopy(v reflect.Value, copied map[copiedPtr]reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := copiedPtr{typ: v.Type(), ptr: v.Pointer()}
+		if c, ok := copied[key]; ok {
+			return c
+		}
+		c := reflect.New(v.Type().Elem())
+		copied[key] = c
+		c.Elem().Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.New(v.Type()).Elem()
+		c.Set(deepCopy(v.Elem(), copied))
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), copied))
+		}
+		return c
+	case reflect.Array:
+		c := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			c.Index(i).Set(deepCopy(v.Index(i), copied))
+		}
+		return c
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		c := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			c.SetMapIndex(deepCopy(iter.Key(), copied), deepCopy(iter.Value(), copied))
+		}
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := c.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i), copied))
+			}
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+//Clone returns a copy of the tree rooted at n as a new root Node, leaving n and its tree unchanged.
+//Values are copied with copier, or shared if copier is nil
+func Clone(n NodeIFace, copier ValueCopier) NodeIFace {
+	return CloneWithFilter(n, func(NodeIFace) bool { return true }, copier)[0]
+}
+
+//CloneWithFilter copies the nodes of the tree rooted at n that pass filter. A copied node whose parent is dropped
+//is added to the copy of its nearest copied ancestor, keeping pre-order, or becomes a new root if there is none,
+//so the result is empty if no node passes and has more than one root if n is dropped.
+//Values are copied with copier, or shared if copier is nil
+func CloneWithFilter(n NodeIFace, filter FilterFunc, copier ValueCopier) []NodeIFace {
+	if copier == nil {
+		copier = ShallowValueCopier
+	}
+	roots := make([]NodeIFace, 0)
+	//keptAncestor maps each walked node to its copy, or to the copy of its nearest copied ancestor if it is dropped
+	keptAncestor := make(map[NodeIFace]NodeIFace)
+	Walk(n, func(node NodeIFace, depth int) WalkAction {
+		var parent NodeIFace
+		if depth > 0 {
+			parent = keptAncestor[node.GetParent()]
+		}
+		if !filter(node) {
+			keptAncestor[node] = parent
+			return WalkContinue
+		}
+		c := NewNode(copier(node.GetValue()), nil)
+		if parent == nil {
+			roots = append(roots, c)
+		} else {
+			parent.AddChild(c)
+		}
+		keptAncestor[node] = c
+		return WalkContinue
+	})
+	return roots
+}
@@ -0,0 +1,105 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestClone_CopiesSubtreeIntoDetachedRoot(t *testing.T) {
+	root, a, _, _, _, _, _ := buildWalkTree()
+	before := tree.Render(root, nil)
+
+	sut := tree.Clone(a, nil)
+	assert.True(t, sut.IsRoot())
+	assert.Equal(t, tree.Render(a, nil), tree.Render(sut, nil))
+	assert.Nil(t, tree.Validate(sut))
+	for _, c := range sut.GetChildren() {
+		assert.Equal(t, sut, c.GetParent())
+	}
+
+	sut.FirstChild().SetValue("x")
+	sut.AddChild(tree.NewNode("y", nil))
+	assert.Equal(t, before, tree.Render(root, nil), "original is unchanged")
+	assert.Equal(t, root, a.GetParent())
+}
+
+type account struct {
+	Name  string
+	Tags  []string
+	Meta  map[string]int
+	Owner *account
+	Any   interface{}
+	Arr   [2]*int
+}
+
+func TestClone_ShallowAndDeepValueCopies(t *testing.T) {
+	one := 1
+	owner := &account{Name: "owner"}
+	acc := &account{Name: "bank", Tags: []string{"cash"}, Meta: map[string]int{"n": 1}, Owner: owner, Any: []int{1}, Arr: [2]*int{&one, &one}}
+	owner.Owner = owner
+	n := tree.NewNode(acc, nil)
+
+	shallow := tree.Clone(n, nil).GetValue().(*account)
+	assert.Same(t, acc, shallow)
+	assert.Same(t, acc, tree.Clone(n, tree.ShallowValueCopier).GetValue())
+
+	deep := tree.Clone(n, tree.DeepValueCopier).GetValue().(*account)
+	assert.NotSame(t, acc, deep)
+	assert.Equal(t, acc, deep)
+	deep.Tags[0] = "changed"
+	deep.Meta["n"] = 2
+	deep.Any.([]int)[0] = 2
+	*deep.Arr[0] = 2
+	assert.Equal(t, "cash", acc.Tags[0])
+	assert.Equal(t, 1, acc.Meta["n"])
+	assert.Equal(t, 1, acc.Any.([]int)[0])
+	assert.Equal(t, 1, one)
+	assert.Same(t, deep.Arr[0], deep.Arr[1], "shared pointers are copied once")
+	assert.NotSame(t, owner, deep.Owner)
+	assert.Same(t, deep.Owner, deep.Owner.Owner, "circular pointers are kept circular")
+
+	assert.Nil(t, tree.DeepValueCopier(nil))
+	assert.Equal(t, "foo", tree.DeepValueCopier("foo"))
+}
+
+func TestCloneWithFilter_ReattachesToNearestKeptAncestor(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	sut := tree.CloneWithFilter(root, func(n tree.NodeIFace) bool {
+		return n.GetValue() != "a" && n.GetValue() != "b"
+	}, nil)
+	expected := `root
+├── d
+├── e
+├── f
+└── c
+`
+	assert.Len(t, sut, 1)
+	assert.Equal(t, expected, tree.Render(sut[0], nil))
+	assert.Equal(t, 7, root.GetSize())
+}
+
+func TestCloneWithFilter_DroppedRootGivesForest(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	sut := tree.CloneWithFilter(root, func(n tree.NodeIFace) bool {
+		return n.GetValue() != "root" && n.GetValue() != "e"
+	}, nil)
+	assert.Len(t, sut, 3)
+	assert.Equal(t, "a\n└── d\n", tree.Render(sut[0], nil))
+	assert.Equal(t, "b\n└── f\n", tree.Render(sut[1], nil))
+	assert.Equal(t, "c\n", tree.Render(sut[2], nil))
+
+	assert.Empty(t, tree.CloneWithFilter(root, func(tree.NodeIFace) bool { return false }, nil))
+}
+
+func TestClone_DeepChain(t *testing.T) {
+	root, _ := buildChain(100000)
+	assert.Equal(t, 100000, tree.Clone(root, nil).GetSize())
+}
@@ -0,0 +1,121 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "fmt"
+
+//Map returns a new tree with the same shape as the tree rooted at root, in which the value of each node is
+//fn of the matching node of root
+func Map(root NodeIFace, fn func(n NodeIFace) interface{}) NodeIFace {
+	var mapped NodeIFace
+	copies := make(map[NodeIFace]NodeIFace)
+	Walk(root, func(n NodeIFace, depth int) WalkAction {
+		c := NewNode(fn(n), nil)
+		if depth == 0 {
+			mapped = c
+		} else {
+			copies[n.GetParent()].AddChild(c)
+		}
+		copies[n] = c
+		return WalkContinue
+	})
+	return mapped
+}
+
+//Fold calls fn for each node of the tree rooted at root in pre-order, passing the result of the previous call,
+//or init for the first, and returns the result of the last call
+func Fold[A any](root NodeIFace, init A, fn func(acc A, n NodeIFace) A) A {
+	acc := init
+	Walk(root, func(n NodeIFace, _ int) WalkAction {
+		acc = fn(acc, n)
+		return WalkContinue
+	})
+	return acc
+}
+
+//FoldPostOrder is like Fold, but calls fn for the nodes in post-order
+func FoldPostOrder[A any](root NodeIFace, init A, fn func(acc A, n NodeIFace) A) A {
+	acc := init
+	WalkPostOrder(root, func(n NodeIFace, _ int) WalkAction {
+		acc = fn(acc, n)
+		return WalkContinue
+	})
+	return acc
+}
+
+//Reduce works out the result for each node of the tree rooted at root from the node and the results for its
+//children, and returns the result for root. Use an AggregateVisitor to keep the results for every node
+func Reduce[R any](root NodeIFace, fn AggregateFunc[R]) R {
+	//in post-order the results for the children of a node are the last ones on the stack when the node is reached
+	results := make([]R, 0)
+	WalkPostOrder(root, func(n NodeIFace, _ int) WalkAction {
+		first := len(results) - len(n.GetChildren())
+		children := make([]R, len(results)-first)
+		copy(children, results[first:])
+		results = append(results[:first], fn(n, children))
+		return WalkContinue
+	})
+	return results[0]
+}
+
+//Any returns true if filter is true for at least one node of the tree rooted at root
+func Any(root NodeIFace, filter FilterFunc) bool {
+	found := false
+	Walk(root, func(n NodeIFace, _ int) WalkAction {
+		if filter(n) {
+			found = true
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	return found
+}
+
+//All returns true if filter is true for every node of the tree rooted at root
+func All(root NodeIFace, filter FilterFunc) bool {
+	return !Any(root, func(n NodeIFace) bool {
+		return !filter(n)
+	})
+}
+
+//Count returns the number of nodes of the tree rooted at root for which filter is true
+func Count(root NodeIFace, filter FilterFunc) int {
+	return Fold(root, 0, func(count int, n NodeIFace) int {
+		if filter(n) {
+			count++
+		}
+		return count
+	})
+}
+
+//Zip returns a new tree with the same shape as the trees rooted at a and b, in which the value of each node is fn
+//of the matching nodes of a and b. An error wrapping ErrShapeMismatch is returned if a and b have different shapes
+func Zip(a, b NodeIFace, fn func(a, b NodeIFace) interface{}) (NodeIFace, error) {
+	type zipFrame struct {
+		a, b NodeIFace
+		dst  NodeIFace
+	}
+	root := NewNode(fn(a, b), nil)
+	stack := []zipFrame{{a: a, b: b, dst: root}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		ac, bc := f.a.GetChildren(), f.b.GetChildren()
+		if len(ac) != len(bc) {
+			return nil, fmt.Errorf("%w: %q has %d children, %q has %d", ErrShapeMismatch,
+				FormatValue(f.a), len(ac), FormatValue(f.b), len(bc))
+		}
+		for i := range ac {
+			c := NewNode(fn(ac[i], bc[i]), nil)
+			f.dst.AddChild(c)
+			stack = append(stack, zipFrame{a: ac[i], b: bc[i], dst: c})
+		}
+	}
+	return root, nil
+}
@@ -0,0 +1,111 @@
+package tree_test
+
+import (
+	"fmt"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestMap(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	before := tree.Render(root, nil)
+	sut := tree.Map(root, func(n tree.NodeIFace) interface{} {
+		return strings.ToUpper(n.GetValue().(string))
+	})
+	expected := `ROOT
+├── A
+│   ├── D
+│   └── E
+├── B
+│   └── F
+└── C
+`
+	assert.Equal(t, expected, tree.Render(sut, nil))
+	assert.Equal(t, before, tree.Render(root, nil))
+	assert.Nil(t, tree.Validate(sut))
+}
+
+func TestFold(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	join := func(acc string, n tree.NodeIFace) string {
+		return acc + tree.FormatValue(n)
+	}
+	assert.Equal(t, ">rootadebfc", tree.Fold(root, ">", join))
+	assert.Equal(t, ">deafbcroot", tree.FoldPostOrder(root, ">", join))
+	assert.Equal(t, 7, tree.Fold(root, 0, func(acc int, _ tree.NodeIFace) int { return acc + 1 }))
+}
+
+func TestReduce(t *testing.T) {
+	root, _, _, _, _, _ := buildBalances()
+	assert.Equal(t, 92, tree.Reduce(root, tree.SumOf(balance)))
+	assert.Equal(t, 2, tree.Reduce(root, func(n tree.NodeIFace, children []int) int {
+		height := 0
+		for _, c := range children {
+			if c+1 > height {
+				height = c + 1
+			}
+		}
+		return height
+	}))
+	assert.Equal(t, "0(100(5))(0(-20)(7))", tree.Reduce(root, func(n tree.NodeIFace, children []string) string {
+		s := tree.FormatValue(n)
+		for _, c := range children {
+			s += "(" + c + ")"
+		}
+		return s
+	}))
+	assert.Equal(t, 1, tree.Reduce(tree.NewNode("leaf", nil), tree.CountNodes()))
+	deep, _ := buildChain(100000)
+	assert.Equal(t, 100000, tree.Reduce(deep, tree.CountNodes()))
+}
+
+func TestAnyAllCount(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	visited := 0
+	assert.True(t, tree.Any(root, func(n tree.NodeIFace) bool {
+		visited++
+		return n.GetValue() == "a"
+	}))
+	assert.Equal(t, 2, visited, "Any stops at the first match")
+	assert.False(t, tree.Any(root, func(n tree.NodeIFace) bool { return n.GetValue() == "x" }))
+
+	assert.True(t, tree.All(root, func(n tree.NodeIFace) bool { return n.GetDepth() <= 2 }))
+	assert.False(t, tree.All(root, tree.NodeIFace.IsLeaf))
+
+	assert.Equal(t, 4, tree.Count(root, tree.NodeIFace.IsLeaf))
+	assert.Equal(t, 0, tree.Count(root, func(tree.NodeIFace) bool { return false }))
+}
+
+func TestZip(t *testing.T) {
+	names, _, _, _, _, _, _ := buildWalkTree()
+	numbers := tree.Map(names, func(n tree.NodeIFace) interface{} {
+		return n.GetDepth()
+	})
+	sut, err := tree.Zip(names, numbers, func(a, b tree.NodeIFace) interface{} {
+		return fmt.Sprintf("%v:%v", a.GetValue(), b.GetValue())
+	})
+	assert.NoError(t, err)
+	expected := `root:0
+├── a:1
+│   ├── d:2
+│   └── e:2
+├── b:1
+│   └── f:2
+└── c:1
+`
+	assert.Equal(t, expected, tree.Render(sut, nil))
+
+	numbers.LastChild().AddChild(tree.NewNode(2, nil))
+	_, err = tree.Zip(names, numbers, func(a, b tree.NodeIFace) interface{} { return nil })
+	assert.ErrorIs(t, err, tree.ErrShapeMismatch)
+	assert.Contains(t, err.Error(), `"c" has 0 children, "1" has 1`)
+}
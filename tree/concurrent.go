@@ -0,0 +1,302 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "sync"
+
+//ConcurrentTree guards a tree with a single read/write lock so that it can be shared between goroutines.
+//Once a tree has been given to a ConcurrentTree it must only be used through the SyncNode values handed
+//out by Root, or within View and Update.
+type ConcurrentTree struct {
+	mu   sync.RWMutex
+	root NodeIFace
+}
+
+//NewConcurrentTree returns a ConcurrentTree guarding the tree rooted at root
+func NewConcurrentTree(root NodeIFace) *ConcurrentTree {
+	return &ConcurrentTree{root: unwrapSync(root)}
+}
+
+//Root returns the root of the tree as a SyncNode
+func (t *ConcurrentTree) Root() NodeIFace {
+	return t.wrap(t.root)
+}
+
+//View calls fn with the root of the unguarded tree while holding the read lock, so that fn sees a consistent
+//snapshot of the tree. fn must not change the tree, nor keep any node after it returns
+func (t *ConcurrentTree) View(fn func(root NodeIFace) error) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return fn(t.root)
+}
+
+//Update calls fn with the root of the unguarded tree while holding the write lock, so that several changes
+//can be made atomically. fn must not keep any node after it returns
+func (t *ConcurrentTree) Update(fn func(root NodeIFace) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return fn(t.root)
+}
+
+//wrap returns n as a SyncNode of this tree, or nil if n is nil
+func (t *ConcurrentTree) wrap(n NodeIFace) NodeIFace {
+	if n == nil {
+		return nil
+	}
+	return SyncNode{tree: t, node: n}
+}
+
+func (t *ConcurrentTree) wrapAll(nodes []NodeIFace) []NodeIFace {
+	wrapped := make([]NodeIFace, len(nodes))
+	for i, n := range nodes {
+		wrapped[i] = t.wrap(n)
+	}
+	return wrapped
+}
+
+//unwrapSync returns the unguarded node of a SyncNode, or n itself if it is not a SyncNode
+func unwrapSync(n NodeIFace) NodeIFace {
+	if s, ok := n.(SyncNode); ok {
+		return s.node
+	}
+	return n
+}
+
+func unwrapSyncAll(nodes []NodeIFace) []NodeIFace {
+	unwrapped := make([]NodeIFace, len(nodes))
+	for i, n := range nodes {
+		unwrapped[i] = unwrapSync(n)
+	}
+	return unwrapped
+}
+
+//SyncNode is a node of a ConcurrentTree. Each method holds the lock of the tree while it runs, and every node
+//it returns is also a SyncNode. SyncNode values are comparable, two SyncNodes are equal if they are the same node
+//of the same tree. A node added to a SyncNode becomes part of the guarded tree and must not be used directly again
+type SyncNode struct {
+	tree *ConcurrentTree
+	node NodeIFace
+}
+
+func (s SyncNode) SetValue(v interface{}) NodeIFace {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	s.node.SetValue(v)
+	return s
+}
+
+func (s SyncNode) GetValue() interface{} {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.GetValue()
+}
+
+func (s SyncNode) AddChild(c NodeIFace) NodeIFace {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	s.node.AddChild(unwrapSync(c))
+	return s
+}
+
+func (s SyncNode) TryAddChild(c NodeIFace) error {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	return s.node.TryAddChild(unwrapSync(c))
+}
+
+func (s SyncNode) InsertChildAt(i int, c NodeIFace) error {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	return s.node.InsertChildAt(i, unwrapSync(c))
+}
+
+func (s SyncNode) MoveChild(from, to int) error {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	return s.node.MoveChild(from, to)
+}
+
+func (s SyncNode) RemoveChild(c NodeIFace) NodeIFace {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	s.node.RemoveChild(unwrapSync(c))
+	return s
+}
+
+func (s SyncNode) RemoveAllChildren() NodeIFace {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	s.node.RemoveAllChildren()
+	return s
+}
+
+//GetChildren returns a copy of the children of this node
+func (s SyncNode) GetChildren() []NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrapAll(s.node.GetChildren())
+}
+
+func (s SyncNode) GetChildAt(i int) NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrap(s.node.GetChildAt(i))
+}
+
+func (s SyncNode) FirstChild() NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrap(s.node.FirstChild())
+}
+
+func (s SyncNode) LastChild() NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrap(s.node.LastChild())
+}
+
+func (s SyncNode) ChildIndex() int {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.ChildIndex()
+}
+
+func (s SyncNode) SetChildren(c ...NodeIFace) NodeIFace {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	s.node.SetChildren(unwrapSyncAll(c)...)
+	return s
+}
+
+func (s SyncNode) SetParent(p NodeIFace) NodeIFace {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	s.node.SetParent(unwrapSync(p))
+	return s
+}
+
+func (s SyncNode) TrySetParent(p NodeIFace) error {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	return s.node.TrySetParent(unwrapSync(p))
+}
+
+func (s SyncNode) MoveTo(p NodeIFace, index int) error {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	return s.node.MoveTo(unwrapSync(p), index)
+}
+
+func (s SyncNode) GetParent() NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrap(s.node.GetParent())
+}
+
+func (s SyncNode) GetAncestors() []NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrapAll(s.node.GetAncestors())
+}
+
+func (s SyncNode) GetAncestorsAndSelf() []NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrapAll(s.node.GetAncestorsAndSelf())
+}
+
+func (s SyncNode) GetSiblings() []NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrapAll(s.node.GetSiblings())
+}
+
+func (s SyncNode) GetSiblingsAndSelf() []NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrapAll(s.node.GetSiblingsAndSelf())
+}
+
+func (s SyncNode) NextSibling() NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrap(s.node.NextSibling())
+}
+
+func (s SyncNode) PrevSibling() NodeIFace {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.tree.wrap(s.node.PrevSibling())
+}
+
+func (s SyncNode) IsRoot() bool {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.IsRoot()
+}
+
+func (s SyncNode) IsChild() bool {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.IsChild()
+}
+
+func (s SyncNode) IsLeaf() bool {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.IsLeaf()
+}
+
+func (s SyncNode) GetDepth() int {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.GetDepth()
+}
+
+func (s SyncNode) GetHeight() int {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.GetHeight()
+}
+
+func (s SyncNode) GetSize() int {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return s.node.GetSize()
+}
+
+//Accept runs the visitor over a consistent snapshot of the tree while holding the read lock, so the visitor
+//must not change the tree. The nodes of results of type NodeIFace, []NodeIFace and [][]NodeIFace are returned
+//as SyncNodes. Any other result, such as the map returned by AggregateVisitor, holds the unguarded nodes of the
+//tree, which may only be used within View or Update. Run such visitors within View instead
+func (s SyncNode) Accept(v VisitorIFace) interface{} {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	switch result := s.node.Accept(v).(type) {
+	case NodeIFace:
+		return s.tree.wrap(result)
+	case []NodeIFace:
+		return s.tree.wrapAll(result)
+	case [][]NodeIFace:
+		wrapped := make([][]NodeIFace, len(result))
+		for i, level := range result {
+			wrapped[i] = s.tree.wrapAll(level)
+		}
+		return wrapped
+	default:
+		return result
+	}
+}
+
+//String returns the tree rooted at this node drawn by Render with the default options
+func (s SyncNode) String() string {
+	s.tree.mu.RLock()
+	defer s.tree.mu.RUnlock()
+	return Render(s.node, nil)
+}
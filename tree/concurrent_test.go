@@ -0,0 +1,201 @@
+package tree_test
+
+import (
+	"fmt"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentTree_SyncNodeImplementsNodeInterface(t *testing.T) {
+	sut := tree.NewConcurrentTree(tree.NewNode("root", nil)).Root()
+	_, ok := sut.(tree.NodeIFace)
+	assert.True(t, ok)
+	assert.IsType(t, tree.SyncNode{}, sut)
+}
+
+//lastChildVisitor returns the last child of the visited node
+type lastChildVisitor struct{}
+
+func (lastChildVisitor) Visit(n tree.NodeIFace) interface{} {
+	return n.LastChild()
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestConcurrentTree_SyncNodesBehaveLikeNodes(t *testing.T) {
+	raw, _, _, _, _, _, _ := buildWalkTree()
+	ct := tree.NewConcurrentTree(raw)
+	root := ct.Root()
+	a := root.FirstChild()
+
+	assert.Equal(t, "a", a.GetValue())
+	assert.Equal(t, a, root.GetChildAt(0))
+	assert.Equal(t, root, a.GetParent())
+	assert.Equal(t, []tree.NodeIFace{root}, a.GetAncestors())
+	assert.Equal(t, root.GetChildAt(1), a.NextSibling())
+	assert.Equal(t, 7, root.GetSize())
+	assert.Equal(t, 2, root.GetHeight())
+	assert.Equal(t, 2, a.FirstChild().GetDepth())
+
+	g := tree.NewNode("g", nil)
+	assert.NoError(t, a.InsertChildAt(0, g))
+	assert.Equal(t, "g", a.FirstChild().GetValue())
+	assert.ErrorIs(t, a.TryAddChild(root), tree.ErrCycle)
+	//edits that read the tree before changing it are made atomically with Update
+	assert.NoError(t, ct.Update(func(r tree.NodeIFace) error {
+		return r.FirstChild().FirstChild().MoveTo(r.LastChild(), 0)
+	}))
+	assert.Equal(t, "g", root.LastChild().FirstChild().GetValue())
+	assert.NoError(t, ct.Update(func(r tree.NodeIFace) error {
+		r.LastChild().RemoveChild(r.LastChild().FirstChild())
+		return nil
+	}))
+	assert.True(t, root.LastChild().IsLeaf())
+	assert.Equal(t, 7, root.GetSize())
+
+	pre := root.Accept(tree.NewPreOrderVisitor()).([]tree.NodeIFace)
+	assert.Equal(t, root, pre[0])
+	assert.Equal(t, a, pre[1])
+	levels := root.Accept(tree.NewLevelsVisitor()).([][]tree.NodeIFace)
+	assert.Equal(t, []tree.NodeIFace{root}, levels[0])
+	assert.Equal(t, root.LastChild(), root.Accept(lastChildVisitor{}))
+	assert.Equal(t, tree.Render(raw, nil), fmt.Sprint(root))
+}
+
+func TestConcurrentTree_ConcurrentMutationAndReads(t *testing.T) {
+	const (
+		writers   = 8
+		readers   = 8
+		perWriter = 200
+	)
+	ct := tree.NewConcurrentTree(tree.NewNode("root", nil))
+	root := ct.Root()
+	for i := 0; i < writers; i++ {
+		root.AddChild(tree.NewNode(fmt.Sprintf("w%d", i), nil))
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			parent := root.GetChildAt(w)
+			for i := 0; i < perWriter; i++ {
+				child := tree.NewNode(i, nil)
+				if err := parent.TryAddChild(child); err != nil {
+					t.Error(err)
+				}
+				if i%10 == 0 {
+					parent.SetValue(fmt.Sprintf("w%d-%d", w, i))
+				}
+				if i%3 == 0 {
+					_ = ct.Update(func(r tree.NodeIFace) error {
+						p := r.GetChildAt(w)
+						p.RemoveChild(p.LastChild())
+						return nil
+					})
+				}
+			}
+		}(w)
+	}
+	//move nodes between the writers' subtrees atomically, keeping the total size unchanged by each move
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < perWriter; i++ {
+			_ = ct.Update(func(r tree.NodeIFace) error {
+				from := r.GetChildAt(i % writers)
+				to := r.GetChildAt((i + 1) % writers)
+				if c := from.FirstChild(); c != nil {
+					return c.MoveTo(to, 0)
+				}
+				return nil
+			})
+		}
+	}()
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				_ = ct.View(func(r tree.NodeIFace) error {
+					nodes := r.Accept(tree.NewPreOrderVisitor()).([]tree.NodeIFace)
+					assert.Equal(t, r.GetSize(), len(nodes))
+					assert.Nil(t, tree.Validate(r))
+					return nil
+				})
+				for _, n := range root.Accept(tree.NewLeafVisitor()).([]tree.NodeIFace) {
+					_ = n.GetValue()
+				}
+				_ = root.GetHeight()
+				_ = fmt.Sprint(root)
+			}
+		}()
+	}
+	wg.Wait()
+
+	//each writer added perWriter nodes and removed every third one
+	removed := (perWriter + 2) / 3
+	assert.Equal(t, 1+writers+writers*(perWriter-removed), root.GetSize())
+	assert.Nil(t, tree.Validate(root))
+}
+
+func TestConcurrentTree_AggregateVisitorUnderReadLock(t *testing.T) {
+	raw, _, _, _, _, _, _ := buildWalkTree()
+	ct := tree.NewConcurrentTree(raw)
+	root := ct.Root()
+	//watching changes the tree, so it is done under the write lock
+	watched := tree.NewAggregateVisitor(tree.CountNodes())
+	assert.NoError(t, ct.Update(func(r tree.NodeIFace) error {
+		return watched.Watch(r)
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			root.LastChild().AddChild(tree.NewNode(i, nil))
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				counts := root.Accept(tree.NewAggregateVisitor(tree.CountNodes())).(map[tree.NodeIFace]int)
+				assert.GreaterOrEqual(t, len(counts), 7)
+				_ = ct.View(func(r tree.NodeIFace) error {
+					assert.Equal(t, r.GetSize(), tree.NewAggregateVisitor(tree.CountNodes()).Result(r))
+					assert.Equal(t, r.GetSize(), tree.Reduce(r, tree.CountNodes()))
+					return nil
+				})
+			}
+		}()
+	}
+	//a visitor shared between goroutines is used by one at a time
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			_ = ct.View(func(r tree.NodeIFace) error {
+				assert.Equal(t, r.GetSize(), watched.Result(r))
+				return nil
+			})
+		}
+	}()
+	wg.Wait()
+
+	assert.Equal(t, 207, root.GetSize())
+	assert.NoError(t, ct.Update(func(tree.NodeIFace) error {
+		watched.Close()
+		return nil
+	}))
+}
@@ -0,0 +1,148 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"strings"
+)
+
+//PlantUMLStyle selects the kind of diagram written by ExportPlantUML
+type PlantUMLStyle int
+
+const (
+	//PlantUMLObject writes an object diagram, as used in docs/tree-graph.puml
+	PlantUMLObject PlantUMLStyle = iota
+	//PlantUMLMindMap writes a mind map
+	PlantUMLMindMap
+	//PlantUMLWBS writes a work breakdown structure
+	PlantUMLWBS
+)
+
+//MermaidStyle selects the kind of diagram written by ExportMermaid
+type MermaidStyle int
+
+const (
+	//MermaidGraph writes a top down flowchart, graph TD
+	MermaidGraph MermaidStyle = iota
+	//MermaidMindmap writes a mind map
+	MermaidMindmap
+)
+
+//DiagramOptions controls how ExportPlantUML and ExportMermaid draw a tree
+type DiagramOptions struct {
+	//Label returns the text drawn for a node. Defaults to FormatValue
+	Label func(NodeIFace) string
+	//MaxDepth is the deepest level drawn below the root, deeper subtrees are collapsed into a single node
+	//showing how many nodes were collapsed. 0 draws all levels
+	MaxDepth int
+}
+
+//diagramNode is a node to be drawn in a diagram
+type diagramNode struct {
+	id, parent, depth int
+	label             string
+}
+
+//diagramNodes returns the nodes to draw for the tree rooted at root in pre-order, with collapsed subtrees
+//replaced by a single node. Ids are the pre-order position of the node and the parent of the root is -1
+func diagramNodes(root NodeIFace, opts *DiagramOptions) []diagramNode {
+	if opts == nil {
+		opts = &DiagramOptions{}
+	}
+	label := opts.Label
+	if label == nil {
+		label = FormatValue
+	}
+	nodes := make([]diagramNode, 0)
+	//path holds the ids of the nodes from the root to the parent of the node being drawn
+	path := make([]int, 0)
+	Walk(root, func(n NodeIFace, depth int) WalkAction {
+		node := diagramNode{id: len(nodes), parent: -1, depth: depth, label: label(n)}
+		path = path[:depth]
+		if depth > 0 {
+			node.parent = path[depth-1]
+		}
+		path = append(path, node.id)
+		nodes = append(nodes, node)
+		if opts.MaxDepth > 0 && depth == opts.MaxDepth && !n.IsLeaf() {
+			nodes = append(nodes, diagramNode{
+				id:     len(nodes),
+				parent: node.id,
+				depth:  depth + 1,
+				label:  fmt.Sprintf("… (%d more)", n.GetSize()-1),
+			})
+			return WalkSkipChildren
+		}
+		return WalkContinue
+	})
+	return nodes
+}
+
+//ExportPlantUML returns the tree rooted at root as a PlantUML diagram of the given style.
+//opts may be nil to use the defaults
+func ExportPlantUML(root NodeIFace, style PlantUMLStyle, opts *DiagramOptions) string {
+	nodes := diagramNodes(root, opts)
+	b := new(strings.Builder)
+	switch style {
+	case PlantUMLMindMap, PlantUMLWBS:
+		kind := "mindmap"
+		if style == PlantUMLWBS {
+			kind = "wbs"
+		}
+		fmt.Fprintf(b, "@start%s\n", kind)
+		for _, n := range nodes {
+			fmt.Fprintf(b, "%s %s\n", strings.Repeat("*", n.depth+1), singleLine(n.label))
+		}
+		fmt.Fprintf(b, "@end%s\n", kind)
+	default:
+		b.WriteString("@startuml\n")
+		for _, n := range nodes {
+			fmt.Fprintf(b, "object \"%s\" as n%d\n", strings.ReplaceAll(singleLine(n.label), `"`, `'`), n.id)
+		}
+		for _, n := range nodes[1:] {
+			fmt.Fprintf(b, "n%d --> n%d\n", n.parent, n.id)
+		}
+		b.WriteString("@enduml\n")
+	}
+	return b.String()
+}
+
+//ExportMermaid returns the tree rooted at root as a Mermaid diagram of the given style.
+//opts may be nil to use the defaults
+func ExportMermaid(root NodeIFace, style MermaidStyle, opts *DiagramOptions) string {
+	nodes := diagramNodes(root, opts)
+	b := new(strings.Builder)
+	switch style {
+	case MermaidMindmap:
+		b.WriteString("mindmap\n")
+		for _, n := range nodes {
+			fmt.Fprintf(b, "%sn%d[\"%s\"]\n", strings.Repeat("  ", n.depth+1), n.id, mermaidEscape(n.label))
+		}
+	default:
+		b.WriteString("graph TD\n")
+		for _, n := range nodes {
+			fmt.Fprintf(b, "\tn%d[\"%s\"]\n", n.id, mermaidEscape(n.label))
+		}
+		for _, n := range nodes[1:] {
+			fmt.Fprintf(b, "\tn%d --> n%d\n", n.parent, n.id)
+		}
+	}
+	return b.String()
+}
+
+//singleLine replaces line breaks in s with spaces
+func singleLine(s string) string {
+	return strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(s)
+}
+
+//mermaidEscape returns s as a single line with double quotes replaced by the Mermaid entity code
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(singleLine(s), `"`, "#quot;")
+}
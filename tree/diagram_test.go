@@ -0,0 +1,120 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestExportPlantUML_Object(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	expected := `@startuml
+object "root" as n0
+object "a" as n1
+object "d" as n2
+object "e" as n3
+object "b" as n4
+object "f" as n5
+object "c" as n6
+n0 --> n1
+n1 --> n2
+n1 --> n3
+n0 --> n4
+n4 --> n5
+n0 --> n6
+@enduml
+`
+	assert.Equal(t, expected, tree.ExportPlantUML(root, tree.PlantUMLObject, nil))
+}
+
+func TestExportPlantUML_MindMapAndWBS(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	body := `* root
+** a
+*** d
+*** e
+** b
+*** f
+** c
+`
+	assert.Equal(t, "@startmindmap\n"+body+"@endmindmap\n", tree.ExportPlantUML(root, tree.PlantUMLMindMap, nil))
+	assert.Equal(t, "@startwbs\n"+body+"@endwbs\n", tree.ExportPlantUML(root, tree.PlantUMLWBS, nil))
+}
+
+func TestExportMermaid_Graph(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	expected := `graph TD
+	n0["root"]
+	n1["a"]
+	n2["d"]
+	n3["e"]
+	n4["b"]
+	n5["f"]
+	n6["c"]
+	n0 --> n1
+	n1 --> n2
+	n1 --> n3
+	n0 --> n4
+	n4 --> n5
+	n0 --> n6
+`
+	assert.Equal(t, expected, tree.ExportMermaid(root, tree.MermaidGraph, nil))
+}
+
+func TestExportMermaid_Mindmap(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	expected := `mindmap
+  n0["root"]
+    n1["a"]
+      n2["d"]
+      n3["e"]
+    n4["b"]
+      n5["f"]
+    n6["c"]
+`
+	assert.Equal(t, expected, tree.ExportMermaid(root, tree.MermaidMindmap, nil))
+}
+
+func TestDiagrams_LabelAndCollapse(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	opts := &tree.DiagramOptions{
+		Label: func(n tree.NodeIFace) string {
+			return strings.ToUpper(n.GetValue().(string)) + "\n\"x\""
+		},
+		MaxDepth: 1,
+	}
+	expected := `@startmindmap
+* ROOT "x"
+** A "x"
+*** … (2 more)
+** B "x"
+*** … (1 more)
+** C "x"
+@endmindmap
+`
+	assert.Equal(t, expected, tree.ExportPlantUML(root, tree.PlantUMLMindMap, opts))
+
+	expected = `graph TD
+	n0["ROOT #quot;x#quot;"]
+	n1["A #quot;x#quot;"]
+	n2["… (2 more)"]
+	n3["B #quot;x#quot;"]
+	n4["… (1 more)"]
+	n5["C #quot;x#quot;"]
+	n0 --> n1
+	n1 --> n2
+	n0 --> n3
+	n3 --> n4
+	n0 --> n5
+`
+	assert.Equal(t, expected, tree.ExportMermaid(root, tree.MermaidGraph, opts))
+	assert.Contains(t, tree.ExportPlantUML(root, tree.PlantUMLObject, opts), "object \"ROOT 'x'\" as n0\n")
+}
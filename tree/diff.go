@@ -0,0 +1,345 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+//KeyFunc returns the identity of a node, which must be comparable and unique within its tree,
+//e.g. the account code held in its value
+type KeyFunc func(n NodeIFace) interface{}
+
+//EditKind is the kind of change made by an Edit
+type EditKind int
+
+const (
+	//EditInsert adds a new leaf node
+	EditInsert EditKind = iota
+	//EditDelete removes a node
+	EditDelete
+	//EditMove moves a node to a new position, under the same or a different parent
+	EditMove
+	//EditUpdate changes the value of a node
+	EditUpdate
+)
+
+func (k EditKind) String() string {
+	switch k {
+	case EditInsert:
+		return "insert"
+	case EditDelete:
+		return "delete"
+	case EditMove:
+		return "move"
+	case EditUpdate:
+		return "update"
+	}
+	return fmt.Sprintf("EditKind(%d)", int(k))
+}
+
+//Edit is a single change to a tree. Nodes are identified by their key.
+//Index is the position of the node in the children of its parent once the whole script has been applied,
+//not counting the children that are deleted by the script
+type Edit struct {
+	Kind EditKind
+	Key  interface{}
+	//ParentKey is the new parent of an inserted or moved node, or the parent of a deleted node
+	ParentKey interface{}
+	//OldParentKey is the parent of a moved node before it was moved
+	OldParentKey interface{}
+	Index        int
+	//OldValue is the value of an updated or deleted node before the change
+	OldValue interface{}
+	//NewValue is the value of an inserted or updated node
+	NewValue interface{}
+}
+
+func (e Edit) String() string {
+	switch e.Kind {
+	case EditInsert:
+		return fmt.Sprintf("+ %v under %v at %d: %v", e.Key, e.ParentKey, e.Index, e.NewValue)
+	case EditDelete:
+		return fmt.Sprintf("- %v from %v: %v", e.Key, e.ParentKey, e.OldValue)
+	case EditMove:
+		return fmt.Sprintf("> %v from %v to %v at %d", e.Key, e.OldParentKey, e.ParentKey, e.Index)
+	case EditUpdate:
+		return fmt.Sprintf("~ %v: %v -> %v", e.Key, e.OldValue, e.NewValue)
+	}
+	return fmt.Sprintf("? %v", e.Key)
+}
+
+//EditScript is a list of edits that changes one tree into another
+type EditScript []Edit
+
+//String renders the script in the style of a unified diff, one edit to a line
+//
+//	--- old
+//	+++ new
+//	+ cash under bank at 0: {cash 5}
+//	> loans from bank to assets at 1
+//	~ bank: {bank 100} -> {bank 105}
+//	- petty from bank: {petty 0}
+func (s EditScript) String() string {
+	b := new(strings.Builder)
+	b.WriteString("--- old\n+++ new\n")
+	for _, e := range s {
+		b.WriteString(singleLine(e.String()))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+//diffNode is a node of the working copy of the old tree that Diff changes as it builds the script
+type diffNode struct {
+	key      interface{}
+	value    interface{}
+	parent   *diffNode
+	children []*diffNode
+}
+
+func (d *diffNode) remove(c *diffNode) {
+	for i, ch := range d.children {
+		if ch == c {
+			d.children = append(d.children[:i], d.children[i+1:]...)
+			break
+		}
+	}
+	c.parent = nil
+}
+
+//liveIndex returns the position of c in the children of this node, not counting those that are deleted
+func (d *diffNode) liveIndex(c *diffNode, deleted func(*diffNode) bool) int {
+	live := 0
+	for _, ch := range d.children {
+		if ch == c {
+			break
+		}
+		if !deleted(ch) {
+			live++
+		}
+	}
+	return live
+}
+
+//insert adds c to the children of this node so that it has i children before it that are not deleted
+func (d *diffNode) insert(i int, c *diffNode, deleted func(*diffNode) bool) {
+	at := actualIndex(len(d.children), i, func(j int) bool {
+		return deleted(d.children[j])
+	})
+	d.children = append(d.children, nil)
+	copy(d.children[at+1:], d.children[at:])
+	d.children[at] = c
+	c.parent = d
+}
+
+//Diff returns the edits that change the tree rooted at oldRoot into the tree rooted at newRoot, where nodes with
+//the same key are the same node. Nodes are inserted, moved and updated in the pre-order of the new tree, then
+//deleted leaves first. Values are compared with reflect.DeepEqual.
+//An error wrapping ErrDuplicateID is returned if a key is not unique within its tree, or ErrShapeMismatch if the
+//roots have different keys
+func Diff(oldRoot, newRoot NodeIFace, key KeyFunc) (EditScript, error) {
+	if _, err := keyIndex(oldRoot, key); err != nil {
+		return nil, err
+	}
+	newKeys, err := keyIndex(newRoot, key)
+	if err != nil {
+		return nil, err
+	}
+	if rk := key(oldRoot); rk != key(newRoot) {
+		return nil, fmt.Errorf("%w: roots have keys %v and %v", ErrShapeMismatch, rk, key(newRoot))
+	}
+	work := make(map[interface{}]*diffNode)
+	var workRoot *diffNode
+	Walk(oldRoot, func(n NodeIFace, depth int) WalkAction {
+		d := &diffNode{key: key(n), value: n.GetValue()}
+		if depth == 0 {
+			workRoot = d
+		} else {
+			d.parent = work[key(n.GetParent())]
+			d.parent.children = append(d.parent.children, d)
+		}
+		work[d.key] = d
+		return WalkContinue
+	})
+	deleted := func(d *diffNode) bool {
+		_, ok := newKeys[d.key]
+		return !ok
+	}
+
+	script := make(EditScript, 0)
+	Walk(newRoot, func(n NodeIFace, depth int) WalkAction {
+		k := key(n)
+		d, exists := work[k]
+		if depth > 0 {
+			p := work[key(n.GetParent())]
+			i := n.ChildIndex()
+			if !exists {
+				d = &diffNode{key: k, value: n.GetValue()}
+				work[k] = d
+				script = append(script, Edit{Kind: EditInsert, Key: k, ParentKey: p.key, Index: i, NewValue: d.value})
+				p.insert(i, d, deleted)
+			} else if d.parent != p || p.liveIndex(d, deleted) != i {
+				script = append(script, Edit{Kind: EditMove, Key: k, ParentKey: p.key, OldParentKey: d.parent.key, Index: i})
+				d.parent.remove(d)
+				p.insert(i, d, deleted)
+			}
+		}
+		if exists && !reflect.DeepEqual(d.value, n.GetValue()) {
+			script = append(script, Edit{Kind: EditUpdate, Key: k, OldValue: d.value, NewValue: n.GetValue()})
+			d.value = n.GetValue()
+		}
+		return WalkContinue
+	})
+
+	//delete leaves first. Any node that is kept has already been moved out of a deleted subtree
+	type deleteFrame struct {
+		node *diffNode
+		next int
+	}
+	stack := []deleteFrame{{node: workRoot}}
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		if f := stack[top]; f.next < len(f.node.children) {
+			stack[top].next++
+			stack = append(stack, deleteFrame{node: f.node.children[f.next]})
+			continue
+		}
+		d := stack[top].node
+		stack = stack[:top]
+		if deleted(d) {
+			script = append(script, Edit{Kind: EditDelete, Key: d.key, ParentKey: d.parent.key, OldValue: d.value})
+		}
+	}
+	return script, nil
+}
+
+//actualIndex returns the position in a list of n children at which to insert a child so that it has i children
+//before it, not counting those for which deleted returns true, or -1 if there are fewer than i such children
+func actualIndex(n, i int, deleted func(j int) bool) int {
+	live := 0
+	for j := 0; j < n; j++ {
+		if live == i {
+			return j
+		}
+		if !deleted(j) {
+			live++
+		}
+	}
+	if live == i {
+		return n
+	}
+	return -1
+}
+
+//keyIndex maps the key of each node of the tree rooted at root to the node, returning an error wrapping
+//ErrDuplicateID if a key is used more than once
+func keyIndex(root NodeIFace, key KeyFunc) (map[interface{}]NodeIFace, error) {
+	nodes := make(map[interface{}]NodeIFace)
+	var err error
+	Walk(root, func(n NodeIFace, _ int) WalkAction {
+		k := key(n)
+		if _, ok := nodes[k]; ok {
+			err = fmt.Errorf("%w: %v", ErrDuplicateID, k)
+			return WalkStop
+		}
+		nodes[k] = n
+		return WalkContinue
+	})
+	return nodes, err
+}
+
+//Patch applies script, as made by Diff, to the tree rooted at root, whose nodes are identified by key. Inserted
+//nodes are new Nodes. An error wrapping ErrNotFound is returned if a node of an edit cannot be found, or the error
+//from the failed change. The edits before the one that failed are not undone
+func Patch(root NodeIFace, script EditScript, key KeyFunc) error {
+	nodes, err := keyIndex(root, key)
+	if err != nil {
+		return err
+	}
+	keys := make(map[NodeIFace]interface{})
+	for k, n := range nodes {
+		keys[n] = k
+	}
+	deletes := make(map[interface{}]bool)
+	for _, e := range script {
+		if e.Kind == EditDelete {
+			deletes[e.Key] = true
+		}
+	}
+	find := func(i int, k interface{}) (NodeIFace, error) {
+		if n, ok := nodes[k]; ok {
+			return n, nil
+		}
+		return nil, fmt.Errorf("%w: edit %d: %v", ErrNotFound, i, k)
+	}
+	//position returns the position in children at which to put a node so that it is at index once the script
+	//has been applied
+	position := func(children []NodeIFace, index int) int {
+		return actualIndex(len(children), index, func(j int) bool {
+			return deletes[keys[children[j]]]
+		})
+	}
+
+	for i, e := range script {
+		n, err := find(i, e.Key)
+		if e.Kind == EditInsert {
+			if err == nil {
+				return fmt.Errorf("%w: edit %d: %v", ErrDuplicateID, i, e.Key)
+			}
+		} else if err != nil {
+			return err
+		}
+		switch e.Kind {
+		case EditInsert, EditMove:
+			p, err := find(i, e.ParentKey)
+			if err != nil {
+				return err
+			}
+			children := make([]NodeIFace, 0)
+			for _, c := range p.GetChildren() {
+				if c != n {
+					children = append(children, c)
+				}
+			}
+			at := position(children, e.Index)
+			if at < 0 {
+				return fmt.Errorf("%w: edit %d: %v", ErrIndexOutOfRange, i, e.Key)
+			}
+			if e.Kind == EditMove {
+				if err := n.MoveTo(p, at); err != nil {
+					return fmt.Errorf("%w: edit %d: %v", err, i, e.Key)
+				}
+				continue
+			}
+			n = NewNode(e.NewValue, nil)
+			if err := p.InsertChildAt(at, n); err != nil {
+				return fmt.Errorf("%w: edit %d: %v", err, i, e.Key)
+			}
+			nodes[e.Key] = n
+			keys[n] = e.Key
+		case EditUpdate:
+			n.SetValue(e.NewValue)
+		case EditDelete:
+			p := n.GetParent()
+			if p == nil {
+				return fmt.Errorf("%w: edit %d: %v is the root", ErrOrphan, i, e.Key)
+			}
+			p.RemoveChild(n)
+			Walk(n, func(d NodeIFace, _ int) WalkAction {
+				delete(nodes, keys[d])
+				delete(keys, d)
+				return WalkContinue
+			})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package tree_test
+
+import (
+	"fmt"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+type ledger struct {
+	Code    string
+	Balance int
+}
+
+func ledgerKey(n tree.NodeIFace) interface{} {
+	return n.GetValue().(ledger).Code
+}
+
+//buildLedger builds a tree from "parent child" pairs, the first parent being the root
+func buildLedger(pairs ...string) tree.NodeIFace {
+	nodes := make(map[string]tree.NodeIFace)
+	var root tree.NodeIFace
+	get := func(code string) tree.NodeIFace {
+		if n, ok := nodes[code]; ok {
+			return n
+		}
+		n := tree.NewNode(ledger{Code: code}, nil)
+		nodes[code] = n
+		return n
+	}
+	for _, pair := range pairs {
+		var parent, child string
+		var balance int
+		fmt.Sscan(pair, &parent, &child, &balance)
+		p := get(parent)
+		if root == nil {
+			root = p
+		}
+		c := get(child)
+		c.SetValue(ledger{Code: child, Balance: balance})
+		p.AddChild(c)
+	}
+	return root
+}
+
+/**
+ *      old                  updated
+ *     assets               assets
+ *     /    \              /  |   \
+ *   bank   loans       loans bank  fx
+ *   /  \      \          |    |
+ * cash petty   l1       l1   cash(5)
+ */
+func TestDiff(t *testing.T) {
+	old := buildLedger("assets bank", "assets loans", "bank cash", "bank petty", "loans l1")
+	updated := buildLedger("assets loans", "assets bank", "assets fx", "loans l1", "bank cash 5")
+
+	script, err := tree.Diff(old, updated, ledgerKey)
+	assert.NoError(t, err)
+	expected := `--- old
++++ new
+> loans from assets to assets at 0
+~ cash: {cash 0} -> {cash 5}
++ fx under assets at 2: {fx 0}
+- petty from bank: {petty 0}
+`
+	assert.Equal(t, expected, script.String())
+	assert.Equal(t, tree.EditMove, script[0].Kind)
+	assert.Equal(t, "move", script[0].Kind.String())
+
+	assert.NoError(t, tree.Patch(old, script, ledgerKey))
+	assert.Equal(t, tree.Render(updated, nil), tree.Render(old, nil))
+	assert.Nil(t, tree.Validate(old))
+}
+
+func TestDiff_IdenticalTreesGiveEmptyScript(t *testing.T) {
+	old := buildLedger("assets bank", "assets loans", "bank cash")
+	script, err := tree.Diff(old, tree.Clone(old, nil), ledgerKey)
+	assert.NoError(t, err)
+	assert.Empty(t, script)
+	assert.Equal(t, "--- old\n+++ new\n", script.String())
+}
+
+func TestDiff_MovesOutOfDeletedSubtree(t *testing.T) {
+	old := buildLedger("assets bank", "bank cash", "cash coins", "assets loans")
+	updated := buildLedger("assets loans", "loans coins")
+	script, err := tree.Diff(old, updated, ledgerKey)
+	assert.NoError(t, err)
+	expected := `--- old
++++ new
+> coins from cash to loans at 0
+- cash from bank: {cash 0}
+- bank from assets: {bank 0}
+`
+	assert.Equal(t, expected, script.String())
+	assert.NoError(t, tree.Patch(old, script, ledgerKey))
+	assert.Equal(t, tree.Render(updated, nil), tree.Render(old, nil))
+}
+
+func TestDiff_Errors(t *testing.T) {
+	old := buildLedger("assets bank")
+	_, err := tree.Diff(old, buildLedger("liabilities bank"), ledgerKey)
+	assert.ErrorIs(t, err, tree.ErrShapeMismatch)
+	_, err = tree.Diff(old, old, func(n tree.NodeIFace) interface{} {
+		return "same"
+	})
+	assert.ErrorIs(t, err, tree.ErrDuplicateID)
+}
+
+func TestPatch_Errors(t *testing.T) {
+	root := buildLedger("assets bank")
+	tests := []struct {
+		name  string
+		edit  tree.Edit
+		isErr error
+	}{
+		{"unknown node", tree.Edit{Kind: tree.EditUpdate, Key: "x"}, tree.ErrNotFound},
+		{"unknown parent", tree.Edit{Kind: tree.EditInsert, Key: "x", ParentKey: "y"}, tree.ErrNotFound},
+		{"duplicate insert", tree.Edit{Kind: tree.EditInsert, Key: "bank", ParentKey: "assets"}, tree.ErrDuplicateID},
+		{"index out of range", tree.Edit{Kind: tree.EditInsert, Key: "x", ParentKey: "assets", Index: 2}, tree.ErrIndexOutOfRange},
+		{"move under self", tree.Edit{Kind: tree.EditMove, Key: "assets", ParentKey: "bank"}, tree.ErrCycle},
+		{"delete root", tree.Edit{Kind: tree.EditDelete, Key: "assets"}, tree.ErrOrphan},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tree.Patch(root, tree.EditScript{tt.edit}, ledgerKey)
+			assert.ErrorIs(t, err, tt.isErr)
+		})
+	}
+	assert.Equal(t, 2, root.GetSize())
+}
+
+//randomLedger builds a random tree whose codes are drawn from a small set, so that two trees share many nodes
+func randomLedger(rnd *rand.Rand) tree.NodeIFace {
+	codes := rnd.Perm(30)
+	nodes := []tree.NodeIFace{tree.NewNode(ledger{Code: "root"}, nil)}
+	for _, c := range codes[:10+rnd.Intn(20)] {
+		n := tree.NewNode(ledger{Code: fmt.Sprint(c), Balance: rnd.Intn(3)}, nil)
+		nodes[rnd.Intn(len(nodes))].AddChild(n)
+		nodes = append(nodes, n)
+	}
+	return nodes[0]
+}
+
+func TestDiff_PatchGivesNewTree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(7))
+	for i := 0; i < 500; i++ {
+		old, updated := randomLedger(rnd), randomLedger(rnd)
+		script, err := tree.Diff(old, updated, ledgerKey)
+		assert.NoError(t, err)
+		patched := tree.Clone(old, nil)
+		assert.NoError(t, tree.Patch(patched, script, ledgerKey))
+		if !assert.Equal(t, tree.Render(updated, nil), tree.Render(patched, nil)) {
+			t.Log(tree.Render(old, nil), script)
+			return
+		}
+	}
+}
@@ -0,0 +1,575 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+//DOTOptions controls how ExportDOT writes a tree
+type DOTOptions struct {
+	//Name is the name of the digraph. Defaults to "tree"
+	Name string
+	//Label returns the label of a node. Defaults to FormatValue
+	Label func(NodeIFace) string
+	//Attributes returns additional attributes of a node, such as shape or color, usually derived from its value
+	Attributes func(NodeIFace) map[string]string
+	//RankByDepth places all nodes of the same depth on the same rank
+	RankByDepth bool
+}
+
+//DOTValueFunc returns the value of a node imported from a DOT graph, given its id and attributes
+type DOTValueFunc func(id string, attrs map[string]string) interface{}
+
+//ExportDOT returns the tree rooted at root as a Graphviz digraph. Nodes are given the ids n0, n1 ...
+//in pre-order. opts may be nil to use the defaults
+func ExportDOT(root NodeIFace, opts *DOTOptions) string {
+	if opts == nil {
+		opts = &DOTOptions{}
+	}
+	name := opts.Name
+	if name == "" {
+		name = "tree"
+	}
+	label := opts.Label
+	if label == nil {
+		label = FormatValue
+	}
+
+	ids := make(map[NodeIFace]string)
+	ranks := make([][]string, 0)
+	b := new(strings.Builder)
+	edges := new(strings.Builder)
+	fmt.Fprintf(b, "digraph %s {\n", dotQuote(name))
+	Walk(root, func(n NodeIFace, depth int) WalkAction {
+		id := fmt.Sprintf("n%d", len(ids))
+		ids[n] = id
+		attrs := map[string]string{}
+		if opts.Attributes != nil {
+			for k, v := range opts.Attributes(n) {
+				attrs[k] = v
+			}
+		}
+		attrs["label"] = label(n)
+		fmt.Fprintf(b, "\t%s [%s];\n", id, dotAttributes(attrs))
+		if depth > 0 {
+			fmt.Fprintf(edges, "\t%s -> %s;\n", ids[n.GetParent()], id)
+		}
+		if depth == len(ranks) {
+			ranks = append(ranks, make([]string, 0))
+		}
+		ranks[depth] = append(ranks[depth], id)
+		return WalkContinue
+	})
+	b.WriteString(edges.String())
+	if opts.RankByDepth {
+		for _, rank := range ranks {
+			fmt.Fprintf(b, "\t{rank=same; %s;}\n", strings.Join(rank, "; "))
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+//dotAttributes returns attrs as a DOT attribute list, sorted by name
+func dotAttributes(attrs map[string]string) string {
+	names := make([]string, 0, len(attrs))
+	for k := range attrs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	list := make([]string, 0, len(names))
+	for _, k := range names {
+		list = append(list, fmt.Sprintf("%s=%s", dotID(k), dotQuote(attrs[k])))
+	}
+	return strings.Join(list, ", ")
+}
+
+//dotID returns s unquoted if it is a plain DOT identifier, otherwise quoted
+func dotID(s string) string {
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r))) {
+			return dotQuote(s)
+		}
+	}
+	switch strings.ToLower(s) {
+	case "", "node", "edge", "graph", "digraph", "subgraph", "strict":
+		return dotQuote(s)
+	}
+	return s
+}
+
+//dotQuote returns s as a quoted DOT string
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+//ImportDOT returns the tree described by a Graphviz digraph. Each node of the graph becomes a node of the tree
+//whose value is returned by valueFn. If valueFn is nil the value is the label attribute of the node, or its id
+//if it has no label. Children are added in the order of the edges. An error wrapping ErrSyntax is returned if the
+//graph cannot be parsed and one wrapping ErrNotATree if the graph is undirected, has no single root, has a node
+//with more than one parent or contains a cycle
+func ImportDOT(src string, valueFn DOTValueFunc) (NodeIFace, error) {
+	if valueFn == nil {
+		valueFn = dotLabel
+	}
+	p := &dotParser{lex: &dotLexer{src: []rune(src)}, attrs: make(map[string]map[string]string)}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	if !p.directed {
+		return nil, fmt.Errorf("%w: graph is undirected", ErrNotATree)
+	}
+	if len(p.ids) == 0 {
+		return nil, fmt.Errorf("%w: graph has no nodes", ErrNotATree)
+	}
+
+	parents := make(map[string]string)
+	for _, e := range p.edges {
+		if e[0] == e[1] {
+			return nil, fmt.Errorf("%w: node %s is its own parent", ErrNotATree, e[1])
+		}
+		if parent, ok := parents[e[1]]; ok {
+			return nil, fmt.Errorf("%w: node %s has parents %s and %s", ErrNotATree, e[1], parent, e[0])
+		}
+		parents[e[1]] = e[0]
+	}
+	roots := make([]string, 0)
+	for _, id := range p.ids {
+		if _, ok := parents[id]; !ok {
+			roots = append(roots, id)
+		}
+	}
+	if len(roots) != 1 {
+		if len(roots) == 0 {
+			return nil, fmt.Errorf("%w: graph has no root", ErrNotATree)
+		}
+		return nil, fmt.Errorf("%w: graph has more than one root %v", ErrNotATree, roots)
+	}
+
+	nodes := make(map[string]NodeIFace, len(p.ids))
+	for _, id := range p.ids {
+		nodes[id] = NewNode(valueFn(id, p.attrs[id]), nil)
+	}
+	for _, e := range p.edges {
+		nodes[e[0]].AddChild(nodes[e[1]])
+	}
+	root := nodes[roots[0]]
+	if root.GetSize() != len(nodes) {
+		return nil, fmt.Errorf("%w: graph contains a cycle", ErrNotATree)
+	}
+	return root, nil
+}
+
+//dotLabel is the default DOTValueFunc
+func dotLabel(id string, attrs map[string]string) interface{} {
+	if label, ok := attrs["label"]; ok {
+		return label
+	}
+	return id
+}
+
+//dotToken is a lexical token of the DOT language
+type dotToken struct {
+	//kind is 'i' for an id, 'e' for an edge operator, 0 at the end of the input or the punctuation character
+	kind rune
+	text string
+	//quoted is true if the id was a quoted or HTML string, so is never a keyword
+	quoted bool
+	line   int
+}
+
+//dotLexer splits DOT source into tokens
+type dotLexer struct {
+	src  []rune
+	pos  int
+	line int
+}
+
+func (l *dotLexer) peekRune(offset int) rune {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+//skip skips white space and comments
+func (l *dotLexer) skip() {
+	atLineStart := l.pos == 0
+	for l.pos < len(l.src) {
+		r := l.src[l.pos]
+		switch {
+		case r == '\n':
+			l.line++
+			l.pos++
+			atLineStart = true
+			continue
+		case unicode.IsSpace(r):
+			l.pos++
+			continue
+		case r == '#' && atLineStart, r == '/' && l.peekRune(1) == '/':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		case r == '/' && l.peekRune(1) == '*':
+			l.pos += 2
+			for l.pos < len(l.src) && !(l.src[l.pos] == '*' && l.peekRune(1) == '/') {
+				if l.src[l.pos] == '\n' {
+					l.line++
+				}
+				l.pos++
+			}
+			l.pos += 2
+			continue
+		}
+		return
+	}
+}
+
+func (l *dotLexer) next() (dotToken, error) {
+	l.skip()
+	tok := dotToken{line: l.line + 1}
+	if l.pos >= len(l.src) {
+		return tok, nil
+	}
+	r := l.src[l.pos]
+	switch {
+	case strings.ContainsRune("{}[];,=:", r):
+		l.pos++
+		tok.kind = r
+	case r == '-' && (l.peekRune(1) == '>' || l.peekRune(1) == '-'):
+		tok.kind = 'e'
+		tok.text = string(l.src[l.pos : l.pos+2])
+		l.pos += 2
+	case r == '"':
+		return l.quoted(tok)
+	case r == '<':
+		return l.html(tok)
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.':
+		start := l.pos
+		for l.pos < len(l.src) {
+			c := l.src[l.pos]
+			if c == '_' || c == '.' || unicode.IsLetter(c) || unicode.IsDigit(c) || (c == '-' && l.pos == start) {
+				l.pos++
+				continue
+			}
+			break
+		}
+		tok.kind = 'i'
+		tok.text = string(l.src[start:l.pos])
+	default:
+		return tok, fmt.Errorf("%w: line %d: unexpected %q", ErrSyntax, tok.line, r)
+	}
+	return tok, nil
+}
+
+//quoted reads a double quoted string
+func (l *dotLexer) quoted(tok dotToken) (dotToken, error) {
+	b := new(strings.Builder)
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		r := l.src[l.pos]
+		switch {
+		case r == '"':
+			l.pos++
+			tok.kind, tok.text, tok.quoted = 'i', b.String(), true
+			return tok, nil
+		case r == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			switch e := l.src[l.pos]; e {
+			case '"', '\\':
+				b.WriteRune(e)
+			case 'n':
+				b.WriteRune('\n')
+			case '\n':
+				//line continuation
+				l.line++
+			default:
+				b.WriteRune('\\')
+				b.WriteRune(e)
+			}
+		default:
+			if r == '\n' {
+				l.line++
+			}
+			b.WriteRune(r)
+		}
+	}
+	return tok, fmt.Errorf("%w: line %d: unterminated string", ErrSyntax, tok.line)
+}
+
+//html reads an HTML string, <...>, which may contain nested <...>
+func (l *dotLexer) html(tok dotToken) (dotToken, error) {
+	start := l.pos + 1
+	depth := 0
+	for ; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case '\n':
+			l.line++
+		}
+		if depth == 0 {
+			tok.kind, tok.text, tok.quoted = 'i', string(l.src[start:l.pos]), true
+			l.pos++
+			return tok, nil
+		}
+	}
+	return tok, fmt.Errorf("%w: line %d: unterminated HTML string", ErrSyntax, tok.line)
+}
+
+//dotParser parses the subset of the DOT language needed to describe a tree: node and edge statements,
+//optionally inside subgraphs. Graph, node and edge default attributes are accepted but ignored
+type dotParser struct {
+	lex      *dotLexer
+	tok      dotToken
+	directed bool
+	//ids are the node ids in order of first appearance
+	ids   []string
+	attrs map[string]map[string]string
+	//edges are the [from, to] pairs in order of appearance
+	edges [][2]string
+}
+
+func (p *dotParser) advance() error {
+	tok, err := p.lex.next()
+	p.tok = tok
+	return err
+}
+
+func (p *dotParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: line %d: %s", ErrSyntax, p.tok.line, fmt.Sprintf(format, args...))
+}
+
+func (p *dotParser) expect(kind rune) error {
+	if p.tok.kind != kind {
+		return p.errorf("expected %q", kind)
+	}
+	return p.advance()
+}
+
+//keyword returns true if the current token is the given keyword
+func (p *dotParser) keyword(kw string) bool {
+	return p.tok.kind == 'i' && !p.tok.quoted && strings.EqualFold(p.tok.text, kw)
+}
+
+func (p *dotParser) parse() error {
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.keyword("strict") {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case p.keyword("digraph"):
+		p.directed = true
+	case p.keyword("graph"):
+	default:
+		return p.errorf("expected graph or digraph")
+	}
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.tok.kind == 'i' {
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	if err := p.block(); err != nil {
+		return err
+	}
+	if p.tok.kind != 0 {
+		return p.errorf("unexpected content after graph")
+	}
+	return nil
+}
+
+//block parses { stmt_list }
+func (p *dotParser) block() error {
+	if err := p.expect('{'); err != nil {
+		return err
+	}
+	for p.tok.kind != '}' {
+		if p.tok.kind == 0 {
+			return p.errorf("expected }")
+		}
+		if err := p.statement(); err != nil {
+			return err
+		}
+		if p.tok.kind == ';' {
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+	}
+	return p.advance()
+}
+
+func (p *dotParser) statement() error {
+	switch {
+	case p.keyword("graph"), p.keyword("node"), p.keyword("edge"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		_, err := p.attrList()
+		return err
+	case p.keyword("subgraph"):
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.kind == 'i' {
+			if err := p.advance(); err != nil {
+				return err
+			}
+		}
+		return p.subgraph()
+	case p.tok.kind == '{':
+		return p.subgraph()
+	case p.tok.kind != 'i':
+		return p.errorf("expected a statement")
+	}
+
+	id := p.tok.text
+	if err := p.advance(); err != nil {
+		return err
+	}
+	if p.tok.kind == '=' {
+		//graph attribute
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.kind != 'i' {
+			return p.errorf("expected a value for %s", id)
+		}
+		return p.advance()
+	}
+	if err := p.port(); err != nil {
+		return err
+	}
+	ids := []string{id}
+	for p.tok.kind == 'e' {
+		if p.tok.text == "--" && p.directed {
+			return p.errorf("-- used in a digraph")
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.kind != 'i' || p.keyword("subgraph") {
+			return p.errorf("expected a node id after edge operator")
+		}
+		ids = append(ids, p.tok.text)
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if err := p.port(); err != nil {
+			return err
+		}
+	}
+	attrs, err := p.attrList()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		p.addNode(id)
+	}
+	if len(ids) == 1 {
+		for k, v := range attrs {
+			p.attrs[id][k] = v
+		}
+		return nil
+	}
+	for i := 1; i < len(ids); i++ {
+		p.edges = append(p.edges, [2]string{ids[i-1], ids[i]})
+	}
+	return nil
+}
+
+//subgraph parses an anonymous or named subgraph body, which may not be used as an edge operand
+func (p *dotParser) subgraph() error {
+	if err := p.block(); err != nil {
+		return err
+	}
+	if p.tok.kind == 'e' {
+		return p.errorf("subgraphs cannot be used in edges")
+	}
+	return nil
+}
+
+//port skips an optional :port[:compass] after a node id
+func (p *dotParser) port() error {
+	for p.tok.kind == ':' {
+		if err := p.advance(); err != nil {
+			return err
+		}
+		if p.tok.kind != 'i' {
+			return p.errorf("expected a port")
+		}
+		if err := p.advance(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//attrList parses zero or more [a=b, c=d] lists
+func (p *dotParser) attrList() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for p.tok.kind == '[' {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		for p.tok.kind != ']' {
+			if p.tok.kind != 'i' {
+				return nil, p.errorf("expected an attribute name")
+			}
+			name := p.tok.text
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			value := "true"
+			if p.tok.kind == '=' {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				if p.tok.kind != 'i' {
+					return nil, p.errorf("expected a value for %s", name)
+				}
+				value = p.tok.text
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+			attrs[name] = value
+			if p.tok.kind == ',' || p.tok.kind == ';' {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, nil
+}
+
+func (p *dotParser) addNode(id string) {
+	if _, ok := p.attrs[id]; !ok {
+		p.ids = append(p.ids, id)
+		p.attrs[id] = make(map[string]string)
+	}
+}
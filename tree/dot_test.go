@@ -0,0 +1,141 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestExportDOT(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	expected := `digraph "tree" {
+	n0 [label="root"];
+	n1 [label="a"];
+	n2 [label="d"];
+	n3 [label="e"];
+	n4 [label="b"];
+	n5 [label="f"];
+	n6 [label="c"];
+	n0 -> n1;
+	n1 -> n2;
+	n1 -> n3;
+	n0 -> n4;
+	n4 -> n5;
+	n0 -> n6;
+}
+`
+	assert.Equal(t, expected, tree.ExportDOT(root, nil))
+}
+
+func TestExportDOT_Options(t *testing.T) {
+	a := tree.NewNode(-5, nil)
+	root := tree.NewNode(10, &[]tree.NodeIFace{a, tree.NewNode(3, nil)})
+	actual := tree.ExportDOT(root, &tree.DOTOptions{
+		Name: "accounts",
+		Label: func(n tree.NodeIFace) string {
+			return "balance \"" + tree.FormatValue(n) + "\""
+		},
+		Attributes: func(n tree.NodeIFace) map[string]string {
+			if n.GetValue().(int) < 0 {
+				return map[string]string{"color": "red", "shape": "box"}
+			}
+			return nil
+		},
+		RankByDepth: true,
+	})
+	expected := `digraph "accounts" {
+	n0 [label="balance \"10\""];
+	n1 [color="red", label="balance \"-5\"", shape="box"];
+	n2 [label="balance \"3\""];
+	n0 -> n1;
+	n0 -> n2;
+	{rank=same; n0;}
+	{rank=same; n1; n2;}
+}
+`
+	assert.Equal(t, expected, actual)
+}
+
+func TestDOT_RoundTrip(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	actual, err := tree.ImportDOT(tree.ExportDOT(root, &tree.DOTOptions{RankByDepth: true}), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tree.Render(root, nil), tree.Render(actual, nil))
+	assert.Nil(t, tree.Validate(actual))
+}
+
+func TestImportDOT_Syntax(t *testing.T) {
+	src := `/* accounts */
+strict digraph "chart of accounts" {
+	rankdir=LR
+	graph [fontsize=10]; node [shape=box]; edge [color=grey]
+	# preprocessor style comment
+	assets [label="Assets", color=blue]
+	assets -> bank:n -> savings // chain
+	subgraph cluster_0 {
+		label = "cash"
+		assets -> cash [weight=2];
+	}
+	{rank=same; bank; cash}
+	"savings" [label=<<b>Savings</b>>]
+}`
+	root, err := tree.ImportDOT(src, nil)
+	assert.NoError(t, err)
+	expected := `Assets
+├── bank
+│   └── <b>Savings</b>
+└── cash
+`
+	assert.Equal(t, expected, tree.Render(root, nil))
+}
+
+func TestImportDOT_ValueFunc(t *testing.T) {
+	src := `digraph { a [weight=3]; a -> b }`
+	root, err := tree.ImportDOT(src, func(id string, attrs map[string]string) interface{} {
+		return id + ":" + attrs["weight"]
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a:3", root.GetValue())
+	assert.Equal(t, "b:", root.GetChildAt(0).GetValue())
+}
+
+func TestImportDOT_RejectsGraphsThatAreNotTrees(t *testing.T) {
+	tests := map[string]string{
+		"undirected":    `graph { a -- b }`,
+		"empty":         `digraph { }`,
+		"two parents":   `digraph { a -> b; a -> c; c -> b }`,
+		"two roots":     `digraph { a -> b; c }`,
+		"self loop":     `digraph { a -> a }`,
+		"no root":       `digraph { a -> b -> a }`,
+		"cycle":         `digraph { r -> a; b -> c -> b }`,
+		"cycle at root": `digraph { r; a -> b -> a; r -> x }`,
+	}
+	for name, src := range tests {
+		_, err := tree.ImportDOT(src, nil)
+		assert.ErrorIs(t, err, tree.ErrNotATree, name)
+	}
+}
+
+func TestImportDOT_SyntaxErrors(t *testing.T) {
+	tests := map[string]string{
+		"not a graph":         `tree { a }`,
+		"missing brace":       `digraph { a -> b`,
+		"unterminated string": `digraph { "a -> b }`,
+		"bad edge":            `digraph { a -> ; }`,
+		"undirected edge":     `digraph { a -- b }`,
+		"subgraph edge":       `digraph { {a b} -> c }`,
+		"trailing content":    `digraph { a } b`,
+		"bad character":       `digraph { a @ b }`,
+	}
+	for name, src := range tests {
+		_, err := tree.ImportDOT(src, nil)
+		assert.ErrorIs(t, err, tree.ErrSyntax, name)
+	}
+}
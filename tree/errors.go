@@ -0,0 +1,44 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "errors"
+
+var (
+	//ErrNilNode is returned when a nil node is given where a node is required
+	ErrNilNode = errors.New("tree: nil node")
+	//ErrSelfParent is returned when a node would become its own parent
+	ErrSelfParent = errors.New("tree: node cannot be its own parent")
+	//ErrCycle is returned when an operation would make a node its own ancestor
+	ErrCycle = errors.New("tree: operation would create a cycle")
+	//ErrAlreadyParented is returned when a node that already has a parent is given a different one
+	ErrAlreadyParented = errors.New("tree: node already has a parent")
+	//ErrIndexOutOfRange is returned when a child position is outside of the children of a node
+	ErrIndexOutOfRange = errors.New("tree: child index out of range")
+	//ErrInconsistentParent is reported when the parent of a node and the children of that parent disagree
+	ErrInconsistentParent = errors.New("tree: parent and children links disagree")
+	//ErrOrphan is reported when the parent of a node cannot be found
+	ErrOrphan = errors.New("tree: parent not found")
+	//ErrDuplicateID is reported when more than one node has the same id
+	ErrDuplicateID = errors.New("tree: duplicate id")
+	//ErrNotATree is returned when a graph cannot be converted to a tree
+	ErrNotATree = errors.New("tree: graph is not a tree")
+	//ErrSyntax is returned when a document cannot be parsed
+	ErrSyntax = errors.New("tree: syntax error")
+	//ErrNotFound is returned when a node cannot be found
+	ErrNotFound = errors.New("tree: node not found")
+	//ErrShapeMismatch is returned when two trees that must have the same shape do not
+	ErrShapeMismatch = errors.New("tree: trees have different shapes")
+	//ErrInvalidInterval is returned when the left and right values of nested set rows are malformed or overlap
+	ErrInvalidInterval = errors.New("tree: invalid nested set interval")
+	//ErrUnsupportedNode is returned when an operation needs a *Node and is given another implementation of NodeIFace
+	ErrUnsupportedNode = errors.New("tree: operation needs a *Node")
+	//ErrTooDeep is returned when a tree is too deep to be encoded
+	ErrTooDeep = errors.New("tree: tree too deep to encode")
+)
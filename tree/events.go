@@ -0,0 +1,187 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+//Event is a change made to a tree of Nodes. It is one of ChildAdded, ChildRemoved, ChildMoved, ChildrenReplaced,
+//ValueChanged or ParentChanged. Each change made by a method of a Node is a single event, e.g. SetChildren is one
+//ChildrenReplaced event rather than a ChildRemoved and ChildAdded for each child
+type Event interface {
+	//GetTarget returns the node that was changed
+	GetTarget() NodeIFace
+}
+
+//ChildAdded is sent by AddChild, TryAddChild and InsertChildAt when Child is added to the children of Target
+type ChildAdded struct {
+	Target NodeIFace
+	Child  NodeIFace
+	//Index is the position of Child in the children of Target
+	Index int
+}
+
+//ChildRemoved is sent by RemoveChild when Child is removed from the children of Target
+type ChildRemoved struct {
+	Target NodeIFace
+	Child  NodeIFace
+	//Index is the position that Child had in the children of Target
+	Index int
+}
+
+//ChildMoved is sent by MoveChild and MoveTo when Child is moved to position Index in the children of Target.
+//For MoveChild OldParent is Target
+type ChildMoved struct {
+	Target NodeIFace
+	Child  NodeIFace
+	//OldParent is the parent of Child before it was moved, nil if it was a root
+	OldParent NodeIFace
+	//OldIndex is the position that Child had in the children of OldParent, -1 if it was a root
+	OldIndex int
+	Index    int
+}
+
+//ChildrenReplaced is sent by SetChildren and RemoveAllChildren when the children of Target are replaced
+type ChildrenReplaced struct {
+	Target      NodeIFace
+	OldChildren []NodeIFace
+	NewChildren []NodeIFace
+}
+
+//ValueChanged is sent by SetValue when the value of Target is changed
+type ValueChanged struct {
+	Target   NodeIFace
+	OldValue interface{}
+	NewValue interface{}
+}
+
+//ParentChanged is sent by SetParent when the parent of Target is set directly.
+//Adding, removing and moving children, and TrySetParent, change their parents with the events above instead
+type ParentChanged struct {
+	Target    NodeIFace
+	OldParent NodeIFace
+	NewParent NodeIFace
+}
+
+func (e ChildAdded) GetTarget() NodeIFace       { return e.Target }
+func (e ChildRemoved) GetTarget() NodeIFace     { return e.Target }
+func (e ChildMoved) GetTarget() NodeIFace       { return e.Target }
+func (e ChildrenReplaced) GetTarget() NodeIFace { return e.Target }
+func (e ValueChanged) GetTarget() NodeIFace     { return e.Target }
+func (e ParentChanged) GetTarget() NodeIFace    { return e.Target }
+
+//Listener is called with each change made to the tree below the node it subscribed to
+type Listener func(e Event)
+
+//Subscribe calls l after every change to this node or any of its descendants, i.e. events bubble up to the
+//listeners of all ancestors of the changed node. A ChildMoved or ParentChanged event is also sent to the listeners
+//of the old parent and its ancestors. Subscribe returns a function that unsubscribes l
+func (n *Node) Subscribe(l Listener) func() {
+	o := &listenerObserver{listener: l}
+	n.observe(o)
+	return func() {
+		n.unobserve(o)
+	}
+}
+
+//observer is told about changes made to the tree below the Node it is registered on
+type observer interface {
+	//checkAdd returns an error if the subtree rooted at c must not be added below p
+	checkAdd(p, c NodeIFace) error
+	//notify is called after a change has been made
+	notify(e Event)
+}
+
+//listenerObserver is the observer for a Listener
+type listenerObserver struct {
+	listener Listener
+}
+
+func (o *listenerObserver) checkAdd(_, _ NodeIFace) error {
+	return nil
+}
+
+func (o *listenerObserver) notify(e Event) {
+	o.listener(e)
+}
+
+func (n *Node) observe(o observer) {
+	n.observers = append(n.observers, o)
+	addWatched(n, 1)
+}
+
+func (n *Node) unobserve(o observer) {
+	for i, ob := range n.observers {
+		if ob == o {
+			n.observers = append(n.observers[:i:i], n.observers[i+1:]...)
+			addWatched(n, -1)
+			return
+		}
+	}
+}
+
+//watchedFrom returns the number of observers on n and its ancestors, found on the nearest Node at or above n.
+//Changes are only passed up a tree that has observers, so that trees without them do not pay for them
+func watchedFrom(n NodeIFace) int {
+	for ; n != nil; n = n.GetParent() {
+		if nn, ok := n.(*Node); ok {
+			return nn.watched
+		}
+	}
+	return 0
+}
+
+//addWatched adds delta to the count of observers above each Node of the tree rooted at n
+func addWatched(n NodeIFace, delta int) {
+	if delta == 0 {
+		return
+	}
+	Walk(n, func(m NodeIFace, _ int) WalkAction {
+		if mn, ok := m.(*Node); ok {
+			mn.watched += delta
+		}
+		return WalkContinue
+	})
+}
+
+//observersOf returns the observers registered on each of the nodes and their ancestors, nearest first and
+//each only once
+func observersOf(nodes ...NodeIFace) []observer {
+	var obs []observer
+	var seen map[observer]bool
+	//visited stops the climb if SetParent has made a cycle
+	var visited map[NodeIFace]bool
+	for _, n := range nodes {
+		if watchedFrom(n) == 0 {
+			continue
+		}
+		if visited == nil {
+			seen = make(map[observer]bool)
+			visited = make(map[NodeIFace]bool)
+		}
+		for p := n; p != nil && !visited[p]; p = p.GetParent() {
+			visited[p] = true
+			pn, ok := p.(*Node)
+			if !ok {
+				continue
+			}
+			for _, o := range pn.observers {
+				if !seen[o] {
+					seen[o] = true
+					obs = append(obs, o)
+				}
+			}
+		}
+	}
+	return obs
+}
+
+//emit sends e to the observers of the nodes and their ancestors
+func emit(e Event, nodes ...NodeIFace) {
+	for _, o := range observersOf(nodes...) {
+		o.notify(e)
+	}
+}
@@ -0,0 +1,164 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+//record subscribes to n and returns the events it receives
+func record(n tree.NodeIFace) (*[]tree.Event, func()) {
+	events := make([]tree.Event, 0)
+	unsubscribe := n.(*tree.Node).Subscribe(func(e tree.Event) {
+		events = append(events, e)
+	})
+	return &events, unsubscribe
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestSubscribe_EachMethodSendsOneEvent(t *testing.T) {
+	root, a, b, c, d, e, f := buildWalkTree()
+	events, unsubscribe := record(root)
+	defer unsubscribe()
+	g := tree.NewNode("g", nil)
+	h := tree.NewNode("h", nil)
+	i := tree.NewNode("i", nil)
+	x := tree.NewNode("x", nil)
+
+	d.SetValue("D")
+	a.AddChild(g)
+	assert.NoError(t, a.InsertChildAt(0, h))
+	assert.NoError(t, a.MoveChild(0, 3))
+	a.RemoveChild(h)
+	assert.NoError(t, e.MoveTo(c, 0))
+	b.SetChildren(h)
+	b.RemoveAllChildren()
+	x.SetParent(c)
+	assert.NoError(t, c.TryAddChild(i))
+	a.RemoveChild(tree.NewNode("y", nil))
+
+	assert.Equal(t, []tree.Event{
+		tree.ValueChanged{Target: d, OldValue: "d", NewValue: "D"},
+		tree.ChildAdded{Target: a, Child: g, Index: 2},
+		tree.ChildAdded{Target: a, Child: h, Index: 0},
+		tree.ChildMoved{Target: a, Child: h, OldParent: a, OldIndex: 0, Index: 3},
+		tree.ChildRemoved{Target: a, Child: h, Index: 3},
+		tree.ChildMoved{Target: c, Child: e, OldParent: a, OldIndex: 1, Index: 0},
+		tree.ChildrenReplaced{Target: b, OldChildren: []tree.NodeIFace{f}, NewChildren: []tree.NodeIFace{h}},
+		tree.ChildrenReplaced{Target: b, OldChildren: []tree.NodeIFace{h}, NewChildren: []tree.NodeIFace{}},
+		tree.ParentChanged{Target: x, OldParent: nil, NewParent: c},
+		tree.ChildAdded{Target: c, Child: i, Index: 1},
+	}, *events)
+	assert.Equal(t, d, (*events)[0].GetTarget())
+}
+
+func TestSubscribe_ParentChangedBubblesFromOldAndNewParents(t *testing.T) {
+	root, a, _, c, _, _, _ := buildWalkTree()
+	other := tree.NewNode("other", nil)
+	x := tree.NewNode("x", nil)
+	rootEvents, unsubscribe := record(root)
+	defer unsubscribe()
+	otherEvents, unsubscribe2 := record(other)
+	defer unsubscribe2()
+
+	x.SetParent(c)
+	x.SetParent(c)
+	x.SetParent(other)
+	assert.Equal(t, []tree.Event{
+		tree.ParentChanged{Target: x, OldParent: nil, NewParent: c},
+		tree.ParentChanged{Target: x, OldParent: c, NewParent: c},
+		tree.ParentChanged{Target: x, OldParent: c, NewParent: other},
+	}, *rootEvents)
+	assert.Equal(t, []tree.Event{
+		tree.ParentChanged{Target: x, OldParent: c, NewParent: other},
+	}, *otherEvents)
+
+	//a subtree moved between trees is seen by both
+	assert.NoError(t, a.MoveTo(other, 0))
+	moved := tree.ChildMoved{Target: other, Child: a, OldParent: root, OldIndex: 0, Index: 0}
+	assert.Equal(t, moved, (*rootEvents)[3])
+	assert.Equal(t, moved, (*otherEvents)[1])
+}
+
+func TestSubscribe_BubblesNearestFirstWithoutRepeats(t *testing.T) {
+	root, a, _, _, d, _, _ := buildWalkTree()
+	order := make([]string, 0)
+	for _, n := range []tree.NodeIFace{root, a, d} {
+		name := n.GetValue().(string)
+		defer n.(*tree.Node).Subscribe(func(tree.Event) {
+			order = append(order, name)
+		})()
+	}
+	d.SetValue("x")
+	assert.Equal(t, []string{"d", "a", "root"}, order)
+
+	order = order[:0]
+	assert.NoError(t, d.MoveTo(a, 1))
+	assert.Equal(t, []string{"a", "root"}, order)
+}
+
+func TestSubscribe_Unsubscribe(t *testing.T) {
+	root, a, _, _, _, _, _ := buildWalkTree()
+	events, unsubscribe := record(root)
+	a.SetValue("A")
+	unsubscribe()
+	unsubscribe()
+	a.SetValue("AA")
+	assert.Len(t, *events, 1)
+}
+
+func TestSubscribe_ListenerMayChangeTheTree(t *testing.T) {
+	root, a, _, _, _, _, _ := buildWalkTree()
+	count := 0
+	defer root.(*tree.Node).Subscribe(func(e tree.Event) {
+		count++
+		if added, ok := e.(tree.ChildAdded); ok && added.Child.IsLeaf() && count < 3 {
+			added.Child.AddChild(tree.NewNode(count, nil))
+		}
+	})()
+	a.AddChild(tree.NewNode("x", nil))
+	assert.Equal(t, 3, count)
+	assert.Equal(t, 10, root.GetSize())
+}
+
+func TestSubscribe_SetParentCycle(t *testing.T) {
+	root, _, _, c, _, _, _ := buildWalkTree()
+	events, unsubscribe := record(root)
+	defer unsubscribe()
+	root.SetParent(c)
+	assert.Len(t, *events, 1)
+	root.SetParent(nil)
+	assert.Len(t, *events, 2)
+}
+
+func TestSubscribe_OnlyTreesWithListenersPassChangesUp(t *testing.T) {
+	root, a, _, _, d, _, _ := buildWalkTree()
+	events, unsubscribe := record(root)
+	defer unsubscribe()
+
+	//building a deep tree does not climb it to look for listeners, although another tree has some
+	other, leaf := buildChain(100000)
+	leaf.AddChild(tree.NewNode("x", nil))
+	assert.Equal(t, 100001, other.GetSize())
+	assert.Empty(t, *events)
+
+	//a subtree taken out of the tree stops sending changes, and starts again when it is put back
+	root.RemoveChild(a)
+	d.SetValue("x")
+	assert.Len(t, *events, 1)
+	root.AddChild(a)
+	d.SetValue("y")
+	assert.Len(t, *events, 3)
+	a.SetParent(nil)
+	d.SetValue("z")
+	assert.Len(t, *events, 4)
+	a.SetParent(root)
+	d.SetValue("d")
+	assert.Len(t, *events, 6)
+}
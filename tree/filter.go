@@ -25,13 +25,5 @@ func NewFilterVisitor(filter FilterFunc) VisitorIFace {
 
 //Visit filters nodes (pre-order traversal) that match the filter and returns []NodeIFace
 func (v *FilterVisitor) Visit(n NodeIFace) interface{} {
-	nodes := make([]NodeIFace, 0)
-	if v.filter(n) {
-		nodes = append(nodes, n)
-	}
-	for _, child := range n.GetChildren() {
-		vNodes := child.Accept(v).([]NodeIFace)
-		nodes = append(nodes, vNodes...)
-	}
-	return nodes
+	return visitPreOrder(n, v, v.filter)
 }
@@ -0,0 +1,197 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"sort"
+)
+
+//TreeIndex maps the keys of the nodes of a tree to the nodes, so that a node can be found without searching
+//the tree. If the root is a *Node, the index is kept up to date as nodes are added, removed or given new values
+//anywhere in the tree, until it is closed. Other trees are indexed as they were when the index was built.
+//
+//While the index is open, TryAddChild, InsertChildAt and MoveTo return an error wrapping ErrDuplicateID rather
+//than add a node whose key is already in the tree. AddChild, SetChildren and SetValue cannot return an error, so
+//any duplicates they make are kept in the index and reported by Err and Duplicates. Get never picks one of the
+//nodes sharing a key, use GetAll to see them all
+type TreeIndex struct {
+	root  NodeIFace
+	key   KeyFunc
+	nodes map[interface{}][]NodeIFace
+	keys  map[NodeIFace]interface{}
+}
+
+//NewTreeIndex returns an index of the tree rooted at root using key to find the key of each node. If any key is
+//used more than once an error wrapping ErrDuplicateID is returned together with the index, which holds every node
+func NewTreeIndex(root NodeIFace, key KeyFunc) (*TreeIndex, error) {
+	x := &TreeIndex{
+		root:  root,
+		key:   key,
+		nodes: make(map[interface{}][]NodeIFace),
+		keys:  make(map[NodeIFace]interface{}),
+	}
+	x.addTree(root)
+	if n, ok := root.(*Node); ok {
+		n.observe(x)
+	}
+	return x, x.Err()
+}
+
+//Close stops the index being kept up to date
+func (x *TreeIndex) Close() {
+	if n, ok := x.root.(*Node); ok {
+		n.unobserve(x)
+	}
+}
+
+//Get returns the node with key k, or nil if there is none or more than one
+func (x *TreeIndex) Get(k interface{}) NodeIFace {
+	if nodes := x.nodes[k]; len(nodes) == 1 {
+		return nodes[0]
+	}
+	return nil
+}
+
+//GetAll returns every node with key k
+func (x *TreeIndex) GetAll(k interface{}) []NodeIFace {
+	nodes := make([]NodeIFace, len(x.nodes[k]))
+	copy(nodes, x.nodes[k])
+	return nodes
+}
+
+//Contains returns true if there is a node with key k
+func (x *TreeIndex) Contains(k interface{}) bool {
+	return len(x.nodes[k]) > 0
+}
+
+//Len returns the number of nodes in the index
+func (x *TreeIndex) Len() int {
+	return len(x.keys)
+}
+
+//Err returns an error wrapping ErrDuplicateID listing the keys used by more than one node, or nil if there are none
+func (x *TreeIndex) Err() error {
+	if dups := x.Duplicates(); len(dups) > 0 {
+		return fmt.Errorf("%w: %v", ErrDuplicateID, dups)
+	}
+	return nil
+}
+
+//Duplicates returns the keys used by more than one node, sorted by their formatted value
+func (x *TreeIndex) Duplicates() []interface{} {
+	dups := make([]interface{}, 0)
+	for k, nodes := range x.nodes {
+		if len(nodes) > 1 {
+			dups = append(dups, k)
+		}
+	}
+	sort.Slice(dups, func(i, j int) bool {
+		return fmt.Sprint(dups[i]) < fmt.Sprint(dups[j])
+	})
+	return dups
+}
+
+func (x *TreeIndex) addTree(root NodeIFace) {
+	Walk(root, func(n NodeIFace, _ int) WalkAction {
+		x.add(n)
+		return WalkContinue
+	})
+}
+
+func (x *TreeIndex) add(n NodeIFace) {
+	k := x.key(n)
+	x.keys[n] = k
+	x.nodes[k] = append(x.nodes[k], n)
+}
+
+func (x *TreeIndex) remove(n NodeIFace) {
+	k, ok := x.keys[n]
+	if !ok {
+		return
+	}
+	delete(x.keys, n)
+	nodes := x.nodes[k]
+	for i, m := range nodes {
+		if m == n {
+			nodes = append(nodes[:i], nodes[i+1:]...)
+			break
+		}
+	}
+	if len(nodes) == 0 {
+		delete(x.nodes, k)
+		return
+	}
+	x.nodes[k] = nodes
+}
+
+//checkAdd returns an error wrapping ErrDuplicateID if a node of the subtree rooted at c has the same key as another
+//node of that subtree or a node of the tree outside it
+func (x *TreeIndex) checkAdd(_, c NodeIFace) error {
+	subtree := make(map[NodeIFace]bool)
+	Walk(c, func(n NodeIFace, _ int) WalkAction {
+		subtree[n] = true
+		return WalkContinue
+	})
+	seen := make(map[interface{}]bool)
+	var err error
+	Walk(c, func(n NodeIFace, _ int) WalkAction {
+		k := x.key(n)
+		if seen[k] {
+			err = fmt.Errorf("%w: %v", ErrDuplicateID, k)
+			return WalkStop
+		}
+		seen[k] = true
+		for _, m := range x.nodes[k] {
+			if !subtree[m] {
+				err = fmt.Errorf("%w: %v", ErrDuplicateID, k)
+				return WalkStop
+			}
+		}
+		return WalkContinue
+	})
+	return err
+}
+
+func (x *TreeIndex) notify(e Event) {
+	switch e := e.(type) {
+	case ChildAdded:
+		x.addTree(e.Child)
+	case ChildRemoved:
+		x.removeTree(e.Child)
+	case ChildrenReplaced:
+		for _, c := range e.OldChildren {
+			x.removeTree(c)
+		}
+		for _, c := range e.NewChildren {
+			x.addTree(c)
+		}
+	case ChildMoved:
+		if e.OldParent == e.Target {
+			return
+		}
+		//the child may have moved into, out of or within the indexed tree
+		x.removeTree(e.Child)
+		if x.root == e.Target || isAncestor(x.root, e.Target) {
+			x.addTree(e.Child)
+		}
+	case ValueChanged:
+		if k, ok := x.keys[e.Target]; ok && k != x.key(e.Target) {
+			x.remove(e.Target)
+			x.add(e.Target)
+		}
+	}
+}
+
+func (x *TreeIndex) removeTree(root NodeIFace) {
+	Walk(root, func(n NodeIFace, _ int) WalkAction {
+		x.remove(n)
+		return WalkContinue
+	})
+}
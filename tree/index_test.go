@@ -0,0 +1,156 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func valueKey(n tree.NodeIFace) interface{} {
+	return n.GetValue()
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestTreeIndex_Get(t *testing.T) {
+	root, a, _, _, _, e, _ := buildWalkTree()
+	sut, err := tree.NewTreeIndex(root, valueKey)
+	assert.NoError(t, err)
+	defer sut.Close()
+	assert.Equal(t, 7, sut.Len())
+	assert.Equal(t, e, sut.Get("e"))
+	assert.Equal(t, []tree.NodeIFace{a}, sut.GetAll("a"))
+	assert.True(t, sut.Contains("root"))
+	assert.Nil(t, sut.Get("x"))
+	assert.Empty(t, sut.GetAll("x"))
+	assert.Empty(t, sut.Duplicates())
+	assert.NoError(t, sut.Err())
+}
+
+func TestTreeIndex_KeptInSync(t *testing.T) {
+	root, a, b, c, d, _, f := buildWalkTree()
+	sut, _ := tree.NewTreeIndex(root, valueKey)
+	defer sut.Close()
+
+	g := tree.NewNode("g", nil)
+	g.AddChild(tree.NewNode("h", nil))
+	d.AddChild(g)
+	assert.Equal(t, g, sut.Get("g"))
+	assert.NotNil(t, sut.Get("h"))
+
+	a.RemoveChild(d)
+	assert.False(t, sut.Contains("d"))
+	assert.False(t, sut.Contains("h"))
+	assert.Equal(t, 6, sut.Len())
+
+	assert.NoError(t, c.InsertChildAt(0, d))
+	assert.Equal(t, d, sut.Get("d"))
+	assert.Equal(t, 9, sut.Len())
+
+	b.RemoveAllChildren()
+	assert.Nil(t, sut.Get("f"))
+	b.SetChildren(f, tree.NewNode("i", nil))
+	assert.Equal(t, f, sut.Get("f"))
+	assert.True(t, sut.Contains("i"))
+
+	f.SetValue("F")
+	assert.Nil(t, sut.Get("f"))
+	assert.Equal(t, f, sut.Get("F"))
+
+	assert.NoError(t, d.MoveTo(b, 0))
+	assert.Equal(t, d, sut.Get("d"))
+	assert.Equal(t, 10, sut.Len())
+	assert.Empty(t, sut.Duplicates())
+
+	sut.Close()
+	a.AddChild(tree.NewNode("j", nil))
+	assert.False(t, sut.Contains("j"))
+}
+
+func TestTreeIndex_RejectsDuplicatesOnCheckedInsert(t *testing.T) {
+	root, a, b, _, _, _, _ := buildWalkTree()
+	sut, _ := tree.NewTreeIndex(root, valueKey)
+	defer sut.Close()
+
+	assert.ErrorIs(t, a.TryAddChild(tree.NewNode("f", nil)), tree.ErrDuplicateID)
+	assert.ErrorIs(t, a.InsertChildAt(0, tree.NewNode("root", nil)), tree.ErrDuplicateID)
+	sub := tree.NewNode("x", nil)
+	sub.AddChild(tree.NewNode("x", nil))
+	assert.ErrorIs(t, a.TryAddChild(sub), tree.ErrDuplicateID)
+	other := tree.NewNode("e", nil)
+	tree.NewNode("p", nil).AddChild(other)
+	assert.ErrorIs(t, other.MoveTo(b, 0), tree.ErrDuplicateID)
+	assert.Equal(t, "p", other.GetParent().GetValue(), "MoveTo changed nothing")
+	assert.Equal(t, 7, root.GetSize())
+
+	assert.NoError(t, a.TryAddChild(tree.NewNode("g", nil)))
+
+	//unchecked changes are recorded
+	b.AddChild(tree.NewNode("a", nil))
+	b.FirstChild().SetValue("d")
+	assert.Equal(t, []interface{}{"a", "d"}, sut.Duplicates())
+	assert.ErrorIs(t, sut.Err(), tree.ErrDuplicateID)
+	assert.EqualError(t, sut.Err(), "tree: duplicate id: [a d]")
+	assert.Len(t, sut.GetAll("a"), 2)
+	assert.Nil(t, sut.Get("a"), "a shared key does not find either node")
+	assert.True(t, sut.Contains("a"))
+
+	b.RemoveChild(b.LastChild())
+	assert.Equal(t, a, sut.Get("a"))
+	assert.Nil(t, sut.Get("d"))
+	b.RemoveChild(b.FirstChild())
+	assert.NotNil(t, sut.Get("d"))
+	assert.NoError(t, sut.Err())
+}
+
+func TestTreeIndex_DuplicatesOnBuild(t *testing.T) {
+	root := tree.NewNode("a", nil)
+	root.AddChild(tree.NewNode("a", nil))
+	sut, err := tree.NewTreeIndex(root, valueKey)
+	assert.ErrorIs(t, err, tree.ErrDuplicateID)
+	assert.Equal(t, 2, sut.Len())
+	assert.Equal(t, err, sut.Err())
+	assert.Nil(t, sut.Get("a"))
+	sut.Close()
+}
+
+func TestTreeIndex_NestedIndexes(t *testing.T) {
+	root, a, _, _, d, _, _ := buildWalkTree()
+	whole, _ := tree.NewTreeIndex(root, valueKey)
+	defer whole.Close()
+	part, _ := tree.NewTreeIndex(a, valueKey)
+	defer part.Close()
+
+	d.AddChild(tree.NewNode("x", nil))
+	assert.True(t, whole.Contains("x"))
+	assert.True(t, part.Contains("x"))
+	root.AddChild(tree.NewNode("y", nil))
+	assert.True(t, whole.Contains("y"))
+	assert.False(t, part.Contains("y"))
+	assert.ErrorIs(t, a.TryAddChild(tree.NewNode("y", nil)), tree.ErrDuplicateID)
+}
+
+func TestTreeIndex_StaticForOtherNodeTypes(t *testing.T) {
+	raw, _, _, _, _, _, _ := buildWalkTree()
+	root := tree.ToPersistent(raw)
+	sut, err := tree.NewTreeIndex(root, valueKey)
+	assert.NoError(t, err)
+	assert.Equal(t, "e", sut.Get("e").GetValue())
+	sut.Close()
+}
+
+func TestTreeIndex_SetParentCycle(t *testing.T) {
+	root, _, _, c, _, _, _ := buildWalkTree()
+	sut, _ := tree.NewTreeIndex(root, valueKey)
+	defer sut.Close()
+	//SetParent is unchecked, so root becomes its own ancestor. Changes must still reach the index
+	root.SetParent(c)
+	c.AddChild(tree.NewNode("x", nil))
+	assert.True(t, sut.Contains("x"))
+	root.SetParent(nil)
+}
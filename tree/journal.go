@@ -0,0 +1,214 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "fmt"
+
+//Journal records the changes made to a tree of Nodes so that they can be undone and redone.
+//Each event is one step, so a MoveTo is undone in one step, as is a SetChildren
+type Journal struct {
+	limit int
+	//done are the steps that can be undone, oldest first
+	done []Event
+	//undone are the steps that can be redone, most recently undone last
+	undone []Event
+	//dropped is the number of steps dropped from the start of done to keep within limit
+	dropped     int
+	checkpoints map[string]int
+	replaying   bool
+	unsubscribe func()
+}
+
+//NewJournal starts recording the changes made to the tree rooted at root. If limit is more than 0 only the last
+//limit steps are kept
+func NewJournal(root *Node, limit int) *Journal {
+	j := &Journal{
+		limit:       limit,
+		done:        make([]Event, 0),
+		undone:      make([]Event, 0),
+		checkpoints: make(map[string]int),
+	}
+	j.unsubscribe = root.Subscribe(j.record)
+	return j
+}
+
+//Close stops recording changes
+func (j *Journal) Close() {
+	j.unsubscribe()
+}
+
+//record adds a change to the history. A new change cannot be redone after, so the steps that were undone and the
+//checkpoints made after the current step are forgotten
+func (j *Journal) record(e Event) {
+	if j.replaying {
+		return
+	}
+	for name, pos := range j.checkpoints {
+		if pos > j.position() {
+			delete(j.checkpoints, name)
+		}
+	}
+	j.undone = j.undone[:0]
+	j.done = append(j.done, e)
+	if j.limit > 0 && len(j.done) > j.limit {
+		drop := len(j.done) - j.limit
+		j.done = append(j.done[:0:0], j.done[drop:]...)
+		j.dropped += drop
+	}
+}
+
+//position returns the number of steps made since the journal was started, not counting those undone
+func (j *Journal) position() int {
+	return j.dropped + len(j.done)
+}
+
+//CanUndo returns the number of steps that can be undone
+func (j *Journal) CanUndo() int {
+	return len(j.done)
+}
+
+//CanRedo returns the number of steps that can be redone
+func (j *Journal) CanRedo() int {
+	return len(j.undone)
+}
+
+//Undo undoes the last step, returning false if there is nothing to undo
+func (j *Journal) Undo() bool {
+	if len(j.done) == 0 {
+		return false
+	}
+	e := j.done[len(j.done)-1]
+	j.done = j.done[:len(j.done)-1]
+	j.replay(invertEvent(e))
+	j.undone = append(j.undone, e)
+	return true
+}
+
+//Redo redoes the last step undone, returning false if there is nothing to redo
+func (j *Journal) Redo() bool {
+	if len(j.undone) == 0 {
+		return false
+	}
+	e := j.undone[len(j.undone)-1]
+	j.undone = j.undone[:len(j.undone)-1]
+	j.replay(e)
+	j.done = append(j.done, e)
+	return true
+}
+
+//replay applies e without recording it
+func (j *Journal) replay(e Event) {
+	j.replaying = true
+	defer func() {
+		j.replaying = false
+	}()
+	applyEvent(e)
+}
+
+//Checkpoint names the current state of the tree, replacing any checkpoint with the same name
+func (j *Journal) Checkpoint(name string) {
+	j.checkpoints[name] = j.position()
+}
+
+//RevertTo undoes, or redoes, steps until the tree is in the state named by the checkpoint. An error wrapping
+//ErrNotFound is returned if there is no such checkpoint, or it is older than the history kept
+func (j *Journal) RevertTo(name string) error {
+	pos, ok := j.checkpoints[name]
+	if !ok {
+		return fmt.Errorf("%w: checkpoint %q", ErrNotFound, name)
+	}
+	if pos < j.dropped {
+		return fmt.Errorf("%w: checkpoint %q is older than the history kept", ErrNotFound, name)
+	}
+	for j.position() > pos {
+		j.Undo()
+	}
+	for j.position() < pos && j.Redo() {
+	}
+	return nil
+}
+
+//invertEvent returns the event that undoes e
+func invertEvent(e Event) Event {
+	switch e := e.(type) {
+	case ValueChanged:
+		return ValueChanged{Target: e.Target, OldValue: e.NewValue, NewValue: e.OldValue}
+	case ChildAdded:
+		return ChildRemoved{Target: e.Target, Child: e.Child, Index: e.Index}
+	case ChildRemoved:
+		return ChildAdded{Target: e.Target, Child: e.Child, Index: e.Index}
+	case ChildMoved:
+		if e.OldParent == nil {
+			return ChildRemoved{Target: e.Target, Child: e.Child, Index: e.Index}
+		}
+		return ChildMoved{Target: e.OldParent, Child: e.Child, OldParent: e.Target, OldIndex: e.Index, Index: e.OldIndex}
+	case ChildrenReplaced:
+		return ChildrenReplaced{Target: e.Target, OldChildren: e.NewChildren, NewChildren: e.OldChildren}
+	case ParentChanged:
+		return ParentChanged{Target: e.Target, OldParent: e.NewParent, NewParent: e.OldParent}
+	}
+	return e
+}
+
+//applyEvent makes the change described by e, without any checks, and sends e to the observers of the tree
+func applyEvent(e Event) {
+	switch e := e.(type) {
+	case ValueChanged:
+		e.Target.SetValue(e.NewValue)
+	case ChildAdded:
+		insertChildAt(e.Target, e.Index, e.Child)
+		emit(e, e.Target)
+	case ChildRemoved:
+		removeChild(e.Target, e.Child)
+		emit(e, e.Target)
+	case ChildMoved:
+		if e.OldParent != nil {
+			removeChild(e.OldParent, e.Child)
+		}
+		insertChildAt(e.Target, e.Index, e.Child)
+		emit(e, e.Target, e.OldParent)
+	case ChildrenReplaced:
+		tn, ok := e.Target.(*Node)
+		if !ok {
+			e.Target.SetChildren(e.NewChildren...)
+			return
+		}
+		tn.removeAllChildren()
+		for _, c := range e.NewChildren {
+			tn.insertChild(len(tn.children), c)
+		}
+		emit(e, e.Target)
+	case ParentChanged:
+		e.Target.SetParent(e.NewParent)
+	}
+}
+
+//insertChildAt inserts c into the children of p without checks or an event if p is a Node
+func insertChildAt(p NodeIFace, i int, c NodeIFace) {
+	if pn, ok := p.(*Node); ok {
+		pn.insertChild(i, c)
+		return
+	}
+	_ = p.InsertChildAt(i, c)
+}
+
+//removeChild removes c from the children of p without an event if p is a Node
+func removeChild(p NodeIFace, c NodeIFace) {
+	pn, ok := p.(*Node)
+	if !ok {
+		p.RemoveChild(c)
+		return
+	}
+	for i, ch := range pn.children {
+		if ch == c {
+			pn.removeChildAt(i)
+			return
+		}
+	}
+}
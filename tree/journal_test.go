@@ -0,0 +1,213 @@
+package tree_test
+
+import (
+	"errors"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"testing"
+)
+
+//nodeState is everything about a node that the journal must put back
+type nodeState struct {
+	value    interface{}
+	parent   tree.NodeIFace
+	children []tree.NodeIFace
+}
+
+func snapshot(nodes []tree.NodeIFace) map[tree.NodeIFace]nodeState {
+	states := make(map[tree.NodeIFace]nodeState)
+	for _, n := range nodes {
+		children := make([]tree.NodeIFace, len(n.GetChildren()))
+		copy(children, n.GetChildren())
+		states[n] = nodeState{value: n.GetValue(), parent: n.GetParent(), children: children}
+	}
+	return states
+}
+
+//randomEdit makes a random change to the tree rooted at root, adding any new node to all
+func randomEdit(r *rand.Rand, root tree.NodeIFace, all *[]tree.NodeIFace) {
+	nodes := make([]tree.NodeIFace, 0)
+	tree.Walk(root, func(n tree.NodeIFace, _ int) tree.WalkAction {
+		nodes = append(nodes, n)
+		return tree.WalkContinue
+	})
+	newNode := func() tree.NodeIFace {
+		n := tree.NewNode(len(*all), nil)
+		*all = append(*all, n)
+		return n
+	}
+	n := nodes[r.Intn(len(nodes))]
+	size := len(n.GetChildren())
+	switch r.Intn(8) {
+	case 0:
+		n.SetValue(r.Intn(100))
+	case 1:
+		n.AddChild(newNode())
+	case 2:
+		_ = n.InsertChildAt(r.Intn(size+1), newNode())
+	case 3:
+		if size > 0 {
+			_ = n.MoveChild(r.Intn(size), r.Intn(size))
+		}
+	case 4:
+		if size > 0 {
+			n.RemoveChild(n.GetChildAt(r.Intn(size)))
+		}
+	case 5:
+		if n != root {
+			p := nodes[r.Intn(len(nodes))]
+			size = len(p.GetChildren())
+			if n.GetParent() == p {
+				size--
+			}
+			_ = n.MoveTo(p, r.Intn(size+1))
+		}
+	case 6:
+		n.SetChildren(newNode(), newNode())
+	case 7:
+		n.RemoveAllChildren()
+	}
+}
+
+func TestJournal_UndoAndRedoRandomEdits(t *testing.T) {
+	r := rand.New(rand.NewSource(23))
+	for round := 0; round < 50; round++ {
+		root, a, b, c, d, e, f := buildWalkTree()
+		all := []tree.NodeIFace{root, a, b, c, d, e, f}
+		j := tree.NewJournal(root.(*tree.Node), 0)
+
+		original := snapshot(all)
+		steps := 1 + r.Intn(40)
+		for i := 0; i < steps; i++ {
+			randomEdit(r, root, &all)
+		}
+		final := snapshot(all)
+		rendered := tree.Render(root, nil)
+
+		for j.Undo() {
+		}
+		assert.Equal(t, original, snapshot(all[:7]))
+		for _, n := range all[7:] {
+			assert.Nil(t, n.GetParent())
+		}
+		assert.Empty(t, tree.Validate(root))
+
+		for j.Redo() {
+		}
+		assert.Equal(t, final, snapshot(all))
+		assert.Equal(t, rendered, tree.Render(root, nil))
+		j.Close()
+	}
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestJournal_UndoRedo(t *testing.T) {
+	root, a, _, c, d, e, _ := buildWalkTree()
+	j := tree.NewJournal(root.(*tree.Node), 0)
+	defer j.Close()
+	assert.False(t, j.Undo())
+	assert.False(t, j.Redo())
+
+	assert.NoError(t, e.MoveTo(c, 0))
+	d.SetValue("D")
+	assert.Equal(t, 2, j.CanUndo())
+
+	assert.True(t, j.Undo())
+	assert.Equal(t, "d", d.GetValue())
+	assert.True(t, j.Undo())
+	assert.Equal(t, a, e.GetParent())
+	assert.Equal(t, 1, e.ChildIndex())
+	assert.Equal(t, 2, j.CanRedo())
+
+	assert.True(t, j.Redo())
+	assert.Equal(t, c, e.GetParent())
+	assert.Equal(t, 0, e.ChildIndex())
+
+	//a new edit cannot be followed by the step that was undone
+	a.RemoveChild(d)
+	assert.Equal(t, 0, j.CanRedo())
+	assert.False(t, j.Redo())
+	assert.Equal(t, "d", d.GetValue())
+}
+
+func TestJournal_Checkpoints(t *testing.T) {
+	root, a, b, _, d, _, f := buildWalkTree()
+	j := tree.NewJournal(root.(*tree.Node), 0)
+	defer j.Close()
+	before := tree.Render(root, nil)
+
+	j.Checkpoint("start")
+	d.SetValue("D")
+	assert.NoError(t, f.MoveTo(a, 0))
+	j.Checkpoint("moved")
+	b.SetValue("B")
+	moved := "root\n├── a\n│   ├── f\n│   ├── D\n│   └── e\n├── b\n└── c\n"
+
+	assert.NoError(t, j.RevertTo("moved"))
+	assert.Equal(t, moved, tree.Render(root, nil))
+	assert.NoError(t, j.RevertTo("start"))
+	assert.Equal(t, before, tree.Render(root, nil))
+	//a checkpoint can be returned to by redoing
+	assert.NoError(t, j.RevertTo("moved"))
+	assert.Equal(t, moved, tree.Render(root, nil))
+
+	assert.True(t, errors.Is(j.RevertTo("missing"), tree.ErrNotFound))
+
+	//a new edit forgets the checkpoints that can no longer be redone
+	assert.NoError(t, j.RevertTo("start"))
+	b.SetValue("B")
+	assert.True(t, errors.Is(j.RevertTo("moved"), tree.ErrNotFound))
+	assert.NoError(t, j.RevertTo("start"))
+	assert.Equal(t, before, tree.Render(root, nil))
+}
+
+func TestJournal_BoundedHistory(t *testing.T) {
+	root, _, _, _, d, _, _ := buildWalkTree()
+	j := tree.NewJournal(root.(*tree.Node), 3)
+	defer j.Close()
+
+	j.Checkpoint("start")
+	for i := 1; i <= 5; i++ {
+		d.SetValue(i)
+	}
+	assert.Equal(t, 3, j.CanUndo())
+	for j.Undo() {
+	}
+	assert.Equal(t, 2, d.GetValue())
+	assert.True(t, errors.Is(j.RevertTo("start"), tree.ErrNotFound))
+}
+
+func TestJournal_Close(t *testing.T) {
+	root, _, _, _, d, _, _ := buildWalkTree()
+	j := tree.NewJournal(root.(*tree.Node), 0)
+	j.Close()
+	d.SetValue("D")
+	assert.False(t, j.Undo())
+	assert.Equal(t, "D", d.GetValue())
+}
+
+func TestJournal_UndoKeepsIndexInSync(t *testing.T) {
+	root, a, _, c, _, e, _ := buildWalkTree()
+	x, err := tree.NewTreeIndex(root, valueKey)
+	assert.NoError(t, err)
+	defer x.Close()
+	j := tree.NewJournal(root.(*tree.Node), 0)
+	defer j.Close()
+
+	a.RemoveChild(e)
+	assert.False(t, x.Contains("e"))
+	c.SetValue("C")
+	j.Undo()
+	j.Undo()
+	assert.True(t, x.Contains("e"))
+	assert.True(t, x.Contains("c"))
+	assert.False(t, x.Contains("C"))
+	assert.Equal(t, 7, x.Len())
+}
@@ -0,0 +1,155 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+//MaxJSONDepth is the depth of the deepest node that MarshalJSON encodes. encoding/json reads documents nested at
+//most 10000 deep and each level of the tree nests two deeper, its node object and its children array. Values that
+//encode as objects or arrays nest deeper still, so lower the limit further. Use ToAdjacencyList or ToNestedSet to
+//store deeper trees
+const MaxJSONDepth = 4999
+
+//ValueEncoder converts a node value into JSON
+type ValueEncoder func(v interface{}) (json.RawMessage, error)
+
+//ValueDecoder converts JSON into a node value
+type ValueDecoder func(data json.RawMessage) (interface{}, error)
+
+//jsonNode is the JSON document form of a node
+type jsonNode struct {
+	Value    json.RawMessage `json:"value"`
+	Children []*jsonNode     `json:"children,omitempty"`
+}
+
+//jsonNull is the JSON encoding of a nil value
+var jsonNull = json.RawMessage("null")
+
+//EncodeValue is the default ValueEncoder. It encodes values using json.Marshal
+func EncodeValue(v interface{}) (json.RawMessage, error) {
+	return json.Marshal(v)
+}
+
+//DecodeValue is the default ValueDecoder. It decodes values using json.Unmarshal into an interface{},
+//so objects become map[string]interface{} and numbers become float64
+func DecodeValue(data json.RawMessage) (interface{}, error) {
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+//DecodeAs is a ValueDecoder that decodes values into a T, e.g. tree.UnmarshalJSON(data, tree.DecodeAs[Account])
+func DecodeAs[T any](data json.RawMessage) (interface{}, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
+
+//MarshalJSON returns the tree rooted at root as a nested {"value":...,"children":[...]} JSON document,
+//encoding node values with EncodeValue. It returns ErrTooDeep if a node is deeper than MaxJSONDepth below root
+func MarshalJSON(root NodeIFace) ([]byte, error) {
+	return MarshalJSONWith(root, EncodeValue)
+}
+
+//MarshalJSONWith returns the tree rooted at root as a nested {"value":...,"children":[...]} JSON document,
+//encoding node values with encode. It returns ErrTooDeep if a node is deeper than MaxJSONDepth below root
+func MarshalJSONWith(root NodeIFace, encode ValueEncoder) ([]byte, error) {
+	var err error
+	//path holds the document nodes from the root to the parent of the node being encoded
+	path := make([]*jsonNode, 0)
+	Walk(root, func(n NodeIFace, depth int) WalkAction {
+		if depth > MaxJSONDepth {
+			err = fmt.Errorf("%w: node at depth %d, most is %d", ErrTooDeep, depth, MaxJSONDepth)
+			return WalkStop
+		}
+		var value json.RawMessage
+		if value, err = encode(n.GetValue()); err != nil {
+			return WalkStop
+		}
+		if len(value) == 0 {
+			value = jsonNull
+		}
+		doc := &jsonNode{Value: value}
+		path = path[:depth]
+		if depth > 0 {
+			path[depth-1].Children = append(path[depth-1].Children, doc)
+		}
+		path = append(path, doc)
+		return WalkContinue
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(path[0])
+}
+
+//UnmarshalJSON returns the tree held in a nested {"value":...,"children":[...]} JSON document, decoding
+//node values with decode. If decode is nil DecodeValue is used. An error wrapping ErrSyntax is returned if a child
+//is null
+func UnmarshalJSON(data []byte, decode ValueDecoder) (NodeIFace, error) {
+	if decode == nil {
+		decode = DecodeValue
+	}
+	doc := new(jsonNode)
+	if err := json.Unmarshal(data, doc); err != nil {
+		return nil, err
+	}
+	type pending struct {
+		doc    *jsonNode
+		parent NodeIFace
+	}
+	var root NodeIFace
+	stack := []pending{{doc: doc}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		raw := p.doc.Value
+		if len(raw) == 0 {
+			raw = jsonNull
+		}
+		v, err := decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		n := NewNode(v, nil)
+		if p.parent == nil {
+			root = n
+		} else {
+			p.parent.AddChild(n)
+		}
+		for i := len(p.doc.Children) - 1; i >= 0; i-- {
+			if p.doc.Children[i] == nil {
+				return nil, fmt.Errorf("%w: child %d of %v is null", ErrSyntax, i, FormatValue(n))
+			}
+			stack = append(stack, pending{doc: p.doc.Children[i], parent: n})
+		}
+	}
+	return root, nil
+}
+
+//MarshalJSON implements json.Marshaler
+func (n *Node) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(n)
+}
+
+//UnmarshalJSON implements json.Unmarshaler. It replaces the value and children of this node with
+//those held in the document, decoding values with DecodeValue
+func (n *Node) UnmarshalJSON(data []byte) error {
+	root, err := UnmarshalJSON(data, DecodeValue)
+	if err != nil {
+		return err
+	}
+	children := root.GetChildren()
+	root.RemoveAllChildren()
+	n.SetValue(root.GetValue()).SetChildren(children...)
+	return nil
+}
@@ -0,0 +1,133 @@
+package tree_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type jsonAccount struct {
+	Name    string `json:"name"`
+	Balance int    `json:"balance"`
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestMarshalJSON(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	data, err := tree.MarshalJSON(root)
+	assert.NoError(t, err)
+	expected := `{"value":"root","children":[` +
+		`{"value":"a","children":[{"value":"d"},{"value":"e"}]},` +
+		`{"value":"b","children":[{"value":"f"}]},` +
+		`{"value":"c"}]}`
+	assert.JSONEq(t, expected, string(data))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	data := `{"value":"root","children":[{"value":"a","children":[{"value":"d"},{"value":"e"}]},{"value":"b"}]}`
+	root, err := tree.UnmarshalJSON([]byte(data), nil)
+	assert.NoError(t, err)
+	values := make([]interface{}, 0)
+	for _, n := range root.Accept(tree.NewPreOrderVisitor()).([]tree.NodeIFace) {
+		values = append(values, n.GetValue())
+	}
+	assert.Equal(t, []interface{}{"root", "a", "d", "e", "b"}, values)
+	assert.Nil(t, tree.Validate(root))
+}
+
+func TestJSON_RoundTripsTypedValues(t *testing.T) {
+	bank := tree.NewNode(jsonAccount{"bank", 100}, nil)
+	cash := tree.NewNode(jsonAccount{"cash", 5}, nil)
+	root := tree.NewNode(jsonAccount{"assets", 0}, &[]tree.NodeIFace{bank, cash})
+
+	data, err := tree.MarshalJSON(root)
+	assert.NoError(t, err)
+	actual, err := tree.UnmarshalJSON(data, tree.DecodeAs[jsonAccount])
+	assert.NoError(t, err)
+	assert.Equal(t, jsonAccount{"assets", 0}, actual.GetValue())
+	assert.Equal(t, jsonAccount{"bank", 100}, actual.GetChildAt(0).GetValue())
+	assert.Equal(t, jsonAccount{"cash", 5}, actual.GetChildAt(1).GetValue())
+}
+
+func TestJSON_NilValues(t *testing.T) {
+	data, err := tree.MarshalJSON(tree.NewNode(nil, nil))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":null}`, string(data))
+
+	root, err := tree.UnmarshalJSON([]byte(`{"children":[{}]}`), nil)
+	assert.NoError(t, err)
+	assert.Nil(t, root.GetValue())
+	assert.Nil(t, root.GetChildAt(0).GetValue())
+}
+
+func TestMarshalJSONWith_CustomEncoder(t *testing.T) {
+	root := tree.NewNode(1, &[]tree.NodeIFace{tree.NewNode(2, nil)})
+	data, err := tree.MarshalJSONWith(root, func(v interface{}) (json.RawMessage, error) {
+		return json.Marshal(map[string]interface{}{"id": v})
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":{"id":1},"children":[{"value":{"id":2}}]}`, string(data))
+}
+
+func TestMarshalJSONWith_EncoderError(t *testing.T) {
+	encErr := errors.New("cannot encode")
+	_, err := tree.MarshalJSONWith(tree.NewNode(1, nil), func(v interface{}) (json.RawMessage, error) {
+		return nil, encErr
+	})
+	assert.ErrorIs(t, err, encErr)
+}
+
+func TestUnmarshalJSON_Errors(t *testing.T) {
+	_, err := tree.UnmarshalJSON([]byte(`{"value":`), nil)
+	assert.Error(t, err)
+
+	_, err = tree.UnmarshalJSON([]byte(`{"value":"not an account"}`), tree.DecodeAs[jsonAccount])
+	assert.Error(t, err)
+
+	sut, err := tree.UnmarshalJSON([]byte(`{"value":1,"children":[{"value":2},null]}`), nil)
+	assert.Nil(t, sut)
+	assert.ErrorIs(t, err, tree.ErrSyntax)
+	assert.EqualError(t, err, "tree: syntax error: child 1 of 1 is null")
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"value":1,"children":[null]}`), tree.NewNode(0, nil)), tree.ErrSyntax)
+}
+
+func TestJSON_MaxDepth(t *testing.T) {
+	root, leaf := buildChain(tree.MaxJSONDepth + 1)
+	data, err := tree.MarshalJSON(root)
+	assert.NoError(t, err)
+	sut, err := tree.UnmarshalJSON(data, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, tree.MaxJSONDepth, sut.GetHeight())
+
+	leaf.AddChild(tree.NewNode("too deep", nil))
+	_, err = tree.MarshalJSON(root)
+	assert.ErrorIs(t, err, tree.ErrTooDeep)
+
+	//encoding/json cannot read a document one level deeper
+	deeper := strings.Replace(string(data), `{"value":0,`, `{"value":0,"children":[{"value":0,`, 1) + "]}"
+	_, err = tree.UnmarshalJSON([]byte(deeper), nil)
+	assert.Error(t, err)
+}
+
+func TestNode_ImplementsJSONMarshalerAndUnmarshaler(t *testing.T) {
+	root := tree.NewNode("root", &[]tree.NodeIFace{tree.NewNode("a", nil)})
+	data, err := json.Marshal(map[string]tree.NodeIFace{"tree": root})
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), `{"tree":{"value":"root"`))
+
+	actual := tree.NewNode("old", &[]tree.NodeIFace{tree.NewNode("old child", nil)}).(*tree.Node)
+	assert.NoError(t, json.Unmarshal([]byte(`{"value":"root","children":[{"value":"a"}]}`), actual))
+	assert.Equal(t, "root", actual.GetValue())
+	assert.Equal(t, 1, len(actual.GetChildren()))
+	assert.Equal(t, "a", actual.GetChildAt(0).GetValue())
+	assert.Equal(t, actual, actual.GetChildAt(0).GetParent())
+}
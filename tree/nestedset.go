@@ -0,0 +1,97 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"sort"
+)
+
+//NestedSetRow is a node flattened into a nested set row. The descendants of a node are the rows whose Left lies
+//between the Left and Right of the node, so a subtree can be selected with a single range query
+type NestedSetRow struct {
+	Value interface{}
+	Left  int
+	Right int
+	//Depth is the number of ancestors of the node
+	Depth int
+}
+
+//ToNestedSet flattens the tree rooted at root into nested set rows in pre-order, numbering from 1
+func ToNestedSet(root NodeIFace) []NestedSetRow {
+	rows := make([]NestedSetRow, 0)
+	//open holds the positions of the rows from the root to the last row added, which have no Right yet
+	open := make([]int, 0)
+	next := 1
+	closeTo := func(depth int) {
+		for len(open) > depth {
+			top := len(open) - 1
+			rows[open[top]].Right = next
+			next++
+			open = open[:top]
+		}
+	}
+	Walk(root, func(n NodeIFace, depth int) WalkAction {
+		closeTo(depth)
+		rows = append(rows, NestedSetRow{Value: n.GetValue(), Left: next, Depth: depth})
+		next++
+		open = append(open, len(rows)-1)
+		return WalkContinue
+	})
+	closeTo(0)
+	return rows
+}
+
+//FromNestedSet builds a tree of Nodes from nested set rows, in any order, and returns its root. Each row becomes a
+//node holding its Value, with children ordered by Left. Gaps in the numbering are allowed, so the rows of a subtree
+//read from a larger tree can be built, but every interval must lie wholly inside or outside every other one and
+//each Depth must be one more than the Depth of its parent.
+//An error wrapping ErrInvalidInterval is returned if an interval is malformed or overlaps another or a depth is
+//wrong, or ErrNotATree if there are no rows or more than one root
+func FromNestedSet(rows []NestedSetRow) (NodeIFace, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: no rows", ErrNotATree)
+	}
+	order := make([]int, len(rows))
+	for i, r := range rows {
+		if r.Left >= r.Right {
+			return nil, fmt.Errorf("%w: row %d: left %d is not less than right %d", ErrInvalidInterval, i, r.Left, r.Right)
+		}
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rows[order[i]].Left < rows[order[j]].Left
+	})
+
+	root := order[0]
+	nodes := make([]NodeIFace, len(rows))
+	nodes[root] = NewNode(rows[root].Value, nil)
+	//stack holds the rows from the root to the parent of the next row
+	stack := []int{root}
+	for _, i := range order[1:] {
+		r := rows[i]
+		for len(stack) > 0 && rows[stack[len(stack)-1]].Right < r.Left {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			return nil, fmt.Errorf("%w: rows %d and %d are both roots", ErrNotATree, root, i)
+		}
+		p := stack[len(stack)-1]
+		if r.Left == rows[p].Left || r.Right >= rows[p].Right {
+			return nil, fmt.Errorf("%w: row %d overlaps row %d", ErrInvalidInterval, i, p)
+		}
+		if r.Depth != rows[p].Depth+1 {
+			return nil, fmt.Errorf("%w: row %d: depth %d, expected %d", ErrInvalidInterval, i, r.Depth, rows[p].Depth+1)
+		}
+		nodes[i] = NewNode(r.Value, nil)
+		nodes[p].AddChild(nodes[i])
+		stack = append(stack, i)
+	}
+	return nodes[root], nil
+}
@@ -0,0 +1,115 @@
+package tree_test
+
+import (
+	"errors"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestToNestedSet(t *testing.T) {
+	root, a, _, _, _, _, _ := buildWalkTree()
+	expected := []tree.NestedSetRow{
+		{Value: "root", Left: 1, Right: 14, Depth: 0},
+		{Value: "a", Left: 2, Right: 7, Depth: 1},
+		{Value: "d", Left: 3, Right: 4, Depth: 2},
+		{Value: "e", Left: 5, Right: 6, Depth: 2},
+		{Value: "b", Left: 8, Right: 11, Depth: 1},
+		{Value: "f", Left: 9, Right: 10, Depth: 2},
+		{Value: "c", Left: 12, Right: 13, Depth: 1},
+	}
+	assert.Equal(t, expected, tree.ToNestedSet(root))
+
+	assert.Equal(t, []tree.NestedSetRow{
+		{Value: "a", Left: 1, Right: 6, Depth: 0},
+		{Value: "d", Left: 2, Right: 3, Depth: 1},
+		{Value: "e", Left: 4, Right: 5, Depth: 1},
+	}, tree.ToNestedSet(a))
+
+	leaf := tree.NewNode("leaf", nil)
+	assert.Equal(t, []tree.NestedSetRow{{Value: "leaf", Left: 1, Right: 2}}, tree.ToNestedSet(leaf))
+}
+
+func TestToNestedSet_Chain(t *testing.T) {
+	root, _ := buildChain(1000)
+	rows := tree.ToNestedSet(root)
+	assert.Len(t, rows, 1000)
+	assert.Equal(t, tree.NestedSetRow{Value: rows[0].Value, Left: 1, Right: 2000}, rows[0])
+	assert.Equal(t, 1000, rows[999].Left)
+	assert.Equal(t, 1001, rows[999].Right)
+	assert.Equal(t, 999, rows[999].Depth)
+}
+
+func TestFromNestedSet(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	rows := tree.ToNestedSet(root)
+	//rows may come back from the database in any order
+	shuffled := []tree.NestedSetRow{rows[5], rows[0], rows[3], rows[6], rows[1], rows[4], rows[2]}
+
+	sut, err := tree.FromNestedSet(shuffled)
+	assert.NoError(t, err)
+	assert.Equal(t, tree.Render(root, nil), tree.Render(sut, nil))
+	assert.Empty(t, tree.Validate(sut))
+	assert.Equal(t, rows, tree.ToNestedSet(sut))
+}
+
+func TestFromNestedSet_Subtree(t *testing.T) {
+	//the rows of a, as selected from the whole tree with left between 2 and 7
+	sut, err := tree.FromNestedSet([]tree.NestedSetRow{
+		{Value: "a", Left: 2, Right: 7, Depth: 1},
+		{Value: "e", Left: 5, Right: 6, Depth: 2},
+		{Value: "d", Left: 3, Right: 4, Depth: 2},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "a\n├── d\n└── e\n", tree.Render(sut, nil))
+}
+
+func TestFromNestedSet_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		rows []tree.NestedSetRow
+		err  error
+	}{
+		{"no rows", []tree.NestedSetRow{}, tree.ErrNotATree},
+		{"two roots", []tree.NestedSetRow{
+			{Value: "a", Left: 1, Right: 2},
+			{Value: "b", Left: 3, Right: 4},
+		}, tree.ErrNotATree},
+		{"left not less than right", []tree.NestedSetRow{
+			{Value: "a", Left: 1, Right: 4},
+			{Value: "b", Left: 3, Right: 3, Depth: 1},
+		}, tree.ErrInvalidInterval},
+		{"overlapping", []tree.NestedSetRow{
+			{Value: "a", Left: 1, Right: 10},
+			{Value: "b", Left: 2, Right: 5, Depth: 1},
+			{Value: "c", Left: 4, Right: 7, Depth: 2},
+		}, tree.ErrInvalidInterval},
+		{"same left", []tree.NestedSetRow{
+			{Value: "a", Left: 1, Right: 10},
+			{Value: "b", Left: 1, Right: 5, Depth: 1},
+		}, tree.ErrInvalidInterval},
+		{"same right", []tree.NestedSetRow{
+			{Value: "a", Left: 1, Right: 10},
+			{Value: "b", Left: 2, Right: 10, Depth: 1},
+		}, tree.ErrInvalidInterval},
+		{"wrong depth", []tree.NestedSetRow{
+			{Value: "a", Left: 1, Right: 6},
+			{Value: "b", Left: 2, Right: 5, Depth: 1},
+			{Value: "c", Left: 3, Right: 4, Depth: 1},
+		}, tree.ErrInvalidInterval},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sut, err := tree.FromNestedSet(test.rows)
+			assert.Nil(t, sut)
+			assert.True(t, errors.Is(err, test.err), "%v", err)
+		})
+	}
+}
@@ -16,16 +16,39 @@ type NodeIFace interface {
 	GetValue() interface{}
 	//AddChild adds a child to the set of children for this node and returns this node
 	AddChild(NodeIFace) NodeIFace
+	//TryAddChild adds a child to this node, returning an error if the child is this node, an ancestor
+	//of this node or already has a parent
+	TryAddChild(NodeIFace) error
+	//InsertChildAt inserts a child at the given position in the children of this node, performing the same
+	//checks as TryAddChild. Index may be between 0 and the number of children inclusive
+	InsertChildAt(i int, c NodeIFace) error
+	//MoveChild moves the child at position from to position to, keeping the order of the other children
+	MoveChild(from, to int) error
 	//RemoveChild removes the child node matching the input parameter and returns this node
 	RemoveChild(NodeIFace) NodeIFace
 	//RemoveAllChildren removes all child nodes of this node (and their children) and returns this node
 	RemoveAllChildren() NodeIFace
 	//GetChildren returns all child nodes of this node
 	GetChildren() []NodeIFace
+	//GetChildAt returns the child at the given position or nil if there is none
+	GetChildAt(i int) NodeIFace
+	//FirstChild returns the first child of this node or nil if it is a leaf
+	FirstChild() NodeIFace
+	//LastChild returns the last child of this node or nil if it is a leaf
+	LastChild() NodeIFace
+	//ChildIndex returns the position of this node in the children of its parent or -1 if it is the root
+	ChildIndex() int
 	//SetChildren replaces all child nodes of this node with new ones and returns this node
 	SetChildren(...NodeIFace) NodeIFace
 	//SetParent sets the parent of this node and returns this node
 	SetParent(NodeIFace) NodeIFace
+	//TrySetParent makes p the parent of this node, adding this node to the end of the children of p, or if p is
+	//nil removes this node from the children of its parent. It returns an error if the parent is this node,
+	//a descendant of this node or this node already has a different parent. Use MoveTo to change the parent
+	TrySetParent(NodeIFace) error
+	//MoveTo detaches this node from its current parent and inserts it into the children of the
+	//new parent at the given index. Nothing is changed if an error is returned
+	MoveTo(p NodeIFace, index int) error
 	//GetParent returns the parent node of this node or nil if none
 	GetParent() NodeIFace
 	//GetAncestors retrieves all ancestors of node excluding current node
@@ -36,6 +59,10 @@ type NodeIFace interface {
 	GetSiblings() []NodeIFace
 	//GetSiblingsAndSelf returns all neighboring nodes (children of same parent), including the current node
 	GetSiblingsAndSelf() []NodeIFace
+	//NextSibling returns the sibling following this node or nil if there is none
+	NextSibling() NodeIFace
+	//PrevSibling returns the sibling preceding this node or nil if there is none
+	PrevSibling() NodeIFace
 	//IsRoot returns true if the node is the root, false otherwise
 	IsRoot() bool
 	//IsChild returns true if the node is a child, false otherwise
@@ -58,6 +85,10 @@ type Node struct {
 	value    interface{}
 	children []NodeIFace
 	parent   NodeIFace
+	//observers are told about changes to the tree rooted at this node
+	observers []observer
+	//watched is the number of observers on this node and its ancestors
+	watched int
 }
 
 //NewNode returns a new Node
@@ -73,7 +104,9 @@ func NewNode(v interface{}, children *[]NodeIFace) NodeIFace {
 }
 
 func (n *Node) SetValue(v interface{}) NodeIFace {
+	old := n.value
 	n.value = v
+	emit(ValueChanged{Target: n, OldValue: old, NewValue: v}, n)
 	return n
 }
 
@@ -82,64 +115,261 @@ func (n *Node) GetValue() interface{} {
 }
 
 func (n *Node) AddChild(c NodeIFace) NodeIFace {
-	c = c.SetParent(n)
-	n.children = append(n.children, c)
+	c = n.insertChild(len(n.children), c)
+	emit(ChildAdded{Target: n, Child: c, Index: len(n.children) - 1}, n)
 	return n
 }
 
+func (n *Node) TryAddChild(c NodeIFace) error {
+	if err := n.checkChild(c); err != nil {
+		return err
+	}
+	n.AddChild(c)
+	return nil
+}
+
+func (n *Node) InsertChildAt(i int, c NodeIFace) error {
+	if err := n.checkChild(c); err != nil {
+		return err
+	}
+	if i < 0 || i > len(n.children) {
+		return ErrIndexOutOfRange
+	}
+	c = n.insertChild(i, c)
+	emit(ChildAdded{Target: n, Child: c, Index: i}, n)
+	return nil
+}
+
+//insertChild makes c a child of this node at position i without any checks or event, and returns the child added
+func (n *Node) insertChild(i int, c NodeIFace) NodeIFace {
+	c = adopt(n, c)
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = c
+	return c
+}
+
+//removeChildAt removes the child at position i without an event
+func (n *Node) removeChildAt(i int) {
+	c := n.children[i]
+	copy(n.children[i:], n.children[i+1:])      // Shift following children down to keep their order.
+	n.children[len(n.children)-1] = nil         // Erase last element (write zero value).
+	n.children = n.children[:len(n.children)-1] // Truncate slice.
+	adopt(nil, c)
+}
+
+//adopt sets the parent of c to p, without an event if c is a Node, and returns c
+func adopt(p, c NodeIFace) NodeIFace {
+	if cn, ok := c.(*Node); ok {
+		cn.setParent(p)
+		return cn
+	}
+	before := watchedFrom(c.GetParent())
+	c = c.SetParent(p)
+	addWatched(c, watchedFrom(p)-before)
+	return c
+}
+
+//setParent sets the parent of this node without an event
+func (n *Node) setParent(p NodeIFace) {
+	n.parent = p
+	addWatched(n, len(n.observers)+watchedFrom(p)-n.watched)
+}
+
+func (n *Node) MoveChild(from, to int) error {
+	if from < 0 || from >= len(n.children) || to < 0 || to >= len(n.children) {
+		return ErrIndexOutOfRange
+	}
+	c := n.children[from]
+	if from < to {
+		copy(n.children[from:to], n.children[from+1:to+1])
+	} else {
+		copy(n.children[to+1:from+1], n.children[to:from])
+	}
+	n.children[to] = c
+	emit(ChildMoved{Target: n, Child: c, OldParent: n, OldIndex: from, Index: to}, n)
+	return nil
+}
+
+//checkChild returns an error if c cannot safely be made a child of this node
+func (n *Node) checkChild(c NodeIFace) error {
+	switch {
+	case c == nil:
+		return ErrNilNode
+	case c == NodeIFace(n):
+		return ErrSelfParent
+	case isAncestor(c, n):
+		return ErrCycle
+	case c.GetParent() != nil:
+		return ErrAlreadyParented
+	}
+	return checkObservers(n, c)
+}
+
+//checkObservers returns an error if an observer of p will not allow c to be added below it
+func checkObservers(p, c NodeIFace) error {
+	for _, o := range observersOf(p) {
+		if err := o.checkAdd(p, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (n *Node) RemoveChild(c NodeIFace) NodeIFace {
 	for i, ch := range n.children {
 		if c == ch {
-			n.children[i] = n.children[len(n.children)-1] // Copy last element to index i.
-			n.children[len(n.children)-1] = nil           // Erase last element (write zero value).
-			n.children = n.children[:len(n.children)-1]   // Truncate slice.
+			n.removeChildAt(i)
+			emit(ChildRemoved{Target: n, Child: c, Index: i}, n)
+			break
 		}
 	}
-	c.SetParent(nil)
 	return n
 }
 
 func (n *Node) RemoveAllChildren() NodeIFace {
-	for _, c := range n.children {
-		c.SetParent(nil)
+	old := n.removeAllChildren()
+	emit(ChildrenReplaced{Target: n, OldChildren: old, NewChildren: make([]NodeIFace, 0)}, n)
+	return n
+}
+
+//removeAllChildren removes all children of this node without an event and returns them
+func (n *Node) removeAllChildren() []NodeIFace {
+	old := n.children
+	for _, c := range old {
+		adopt(nil, c)
 	}
 	n.children = make([]NodeIFace, 0)
-	return n
+	return old
 }
 
 func (n *Node) GetChildren() []NodeIFace {
 	return n.children
 }
 
-func (n *Node) SetChildren(c ...NodeIFace) NodeIFace {
-	for _, child := range n.GetChildren() {
-		child.SetParent(nil)
+func (n *Node) GetChildAt(i int) NodeIFace {
+	if i < 0 || i >= len(n.children) {
+		return nil
+	}
+	return n.children[i]
+}
+
+func (n *Node) FirstChild() NodeIFace {
+	return n.GetChildAt(0)
+}
+
+func (n *Node) LastChild() NodeIFace {
+	return n.GetChildAt(len(n.children) - 1)
+}
+
+func (n *Node) ChildIndex() int {
+	if n.IsRoot() {
+		return -1
+	}
+	for i, c := range n.GetParent().GetChildren() {
+		if c == NodeIFace(n) {
+			return i
+		}
 	}
+	return -1
+}
+
+func (n *Node) SetChildren(c ...NodeIFace) NodeIFace {
+	old := n.removeAllChildren()
 	for _, cc := range c {
-		n = n.AddChild(cc).(*Node)
+		n.insertChild(len(n.children), cc)
 	}
+	children := make([]NodeIFace, len(n.children))
+	copy(children, n.children)
+	emit(ChildrenReplaced{Target: n, OldChildren: old, NewChildren: children}, n)
 	return n
 }
 
 func (n *Node) SetParent(p NodeIFace) NodeIFace {
-	n.parent = p
+	old := n.parent
+	n.setParent(p)
+	emit(ParentChanged{Target: n, OldParent: old, NewParent: p}, n, old)
 	return n
 }
 
+func (n *Node) TrySetParent(p NodeIFace) error {
+	switch {
+	case p == nil:
+		//detaching is always allowed
+	case p == NodeIFace(n):
+		return ErrSelfParent
+	case isAncestor(n, p):
+		return ErrCycle
+	case n.parent != nil && n.parent != p:
+		return ErrAlreadyParented
+	}
+	if p == nil {
+		if old := n.parent; old != nil {
+			old.RemoveChild(n)
+		}
+		if n.parent != nil {
+			//the old parent did not hold this node as a child
+			n.SetParent(nil)
+		}
+		return nil
+	}
+	if containsNode(p.GetChildren(), n) {
+		return nil
+	}
+	if err := checkObservers(p, n); err != nil {
+		return err
+	}
+	//the parent link is kept, so AddChild rather than TryAddChild
+	p.AddChild(n)
+	return nil
+}
+
+func (n *Node) MoveTo(p NodeIFace, index int) error {
+	switch {
+	case p == nil:
+		return ErrNilNode
+	case p == NodeIFace(n):
+		return ErrSelfParent
+	case isAncestor(n, p):
+		return ErrCycle
+	}
+	size := len(p.GetChildren())
+	if n.GetParent() == p {
+		size--
+	}
+	if index < 0 || index > size {
+		return ErrIndexOutOfRange
+	}
+	if err := checkObservers(p, n); err != nil {
+		return err
+	}
+	old, oldIndex := n.GetParent(), n.ChildIndex()
+	if on, ok := old.(*Node); ok {
+		on.removeChildAt(oldIndex)
+	} else if old != nil {
+		old.RemoveChild(n)
+	}
+	if pn, ok := p.(*Node); ok {
+		pn.insertChild(index, n)
+	} else if err := p.InsertChildAt(index, n); err != nil {
+		return err
+	}
+	emit(ChildMoved{Target: p, Child: n, OldParent: old, OldIndex: oldIndex, Index: index}, p, old)
+	return nil
+}
+
 func (n *Node) GetParent() NodeIFace {
 	return n.parent
 }
 
 func (n *Node) GetAncestors() []NodeIFace {
 	parents := make([]NodeIFace, 0)
-	node := n
-	for {
-		if p := node.GetParent(); p != nil {
-			parents = append([]NodeIFace{p}, parents...)
-			node = p.(*Node)
-			continue
-		}
-		break
+	for p := n.GetParent(); p != nil; p = p.GetParent() {
+		parents = append(parents, p)
+	}
+	//reverse so that the root comes first
+	for i, j := 0, len(parents)-1; i < j; i, j = i+1, j-1 {
+		parents[i], parents[j] = parents[j], parents[i]
 	}
 	return parents
 }
@@ -151,12 +381,10 @@ func (n *Node) GetAncestorsAndSelf() []NodeIFace {
 }
 
 func (n *Node) GetSiblings() []NodeIFace {
-	siblings := n.GetSiblingsAndSelf()
-	for i, v := range siblings {
-		if v == n {
-			siblings[i] = siblings[len(siblings)-1] // Copy last element to index i.
-			siblings[len(siblings)-1] = nil         // Erase last element (write zero value).
-			siblings = siblings[:len(siblings)-1]   // Truncate slice.
+	siblings := make([]NodeIFace, 0)
+	for _, v := range n.GetSiblingsAndSelf() {
+		if v != NodeIFace(n) {
+			siblings = append(siblings, v)
 		}
 	}
 	return siblings
@@ -166,6 +394,20 @@ func (n *Node) GetSiblingsAndSelf() []NodeIFace {
 	return n.GetParent().GetChildren()
 }
 
+func (n *Node) NextSibling() NodeIFace {
+	if i := n.ChildIndex(); i >= 0 {
+		return n.GetParent().GetChildAt(i + 1)
+	}
+	return nil
+}
+
+func (n *Node) PrevSibling() NodeIFace {
+	if i := n.ChildIndex(); i > 0 {
+		return n.GetParent().GetChildAt(i - 1)
+	}
+	return nil
+}
+
 func (n *Node) IsRoot() bool {
 	return n.parent == nil
 }
@@ -179,44 +421,43 @@ func (n *Node) IsLeaf() bool {
 }
 
 func (n *Node) GetDepth() int {
-	if n.IsRoot() {
-		return 0
+	depth := 0
+	for p := n.GetParent(); p != nil; p = p.GetParent() {
+		depth++
 	}
-	return n.GetParent().GetDepth() + 1
+	return depth
 }
 
 func (n *Node) GetHeight() int {
-	if n.IsLeaf() {
-		return 0
-	}
-	heights := make([]int, 0)
-	for _, c := range n.GetChildren() {
-		heights = append(heights, c.GetHeight())
-	}
-	return maxIntSlice(heights) + 1
-}
-
-func maxIntSlice(v []int) (m int) {
-	if len(v) > 0 {
-		m = v[0]
-	}
-	for i := 1; i < len(v); i++ {
-		if v[i] > m {
-			m = v[i]
+	height := 0
+	Walk(n, func(_ NodeIFace, depth int) WalkAction {
+		if depth > height {
+			height = depth
 		}
-	}
-	return
+		return WalkContinue
+	})
+	return height
 }
 
 func (n *Node) GetSize() int {
-	size := 1
-
-	for _, child := range n.GetChildren() {
-		size += child.GetSize()
-	}
+	size := 0
+	Walk(n, func(NodeIFace, int) WalkAction {
+		size++
+		return WalkContinue
+	})
 	return size
 }
 
 func (n *Node) Accept(v VisitorIFace) interface{} {
 	return v.Visit(n)
 }
+
+//isAncestor returns true if a is an ancestor of n
+func isAncestor(a, n NodeIFace) bool {
+	for p := n.GetParent(); p != nil; p = p.GetParent() {
+		if p == a {
+			return true
+		}
+	}
+	return false
+}
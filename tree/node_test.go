@@ -193,3 +193,237 @@ func TestNode_GetSize(t *testing.T) {
 	assert.Equal(t, 6, child3.GetSize())
 	assert.Equal(t, 1, child2.GetSize())
 }
+
+func TestNode_TryAddChild(t *testing.T) {
+	root := tree.NewNode(0, nil)
+	child := tree.NewNode(1, nil)
+	assert.NoError(t, root.TryAddChild(child))
+	assert.Equal(t, []tree.NodeIFace{child}, root.GetChildren())
+	assert.Equal(t, root, child.GetParent())
+}
+
+func TestNode_TryAddChildRejectsNil(t *testing.T) {
+	assert.ErrorIs(t, tree.NewNode(0, nil).TryAddChild(nil), tree.ErrNilNode)
+}
+
+func TestNode_TryAddChildRejectsSelf(t *testing.T) {
+	root := tree.NewNode(0, nil)
+	assert.ErrorIs(t, root.TryAddChild(root), tree.ErrSelfParent)
+	assert.True(t, root.IsLeaf())
+}
+
+func TestNode_TryAddChildRejectsCycle(t *testing.T) {
+	child2 := tree.NewNode(2, nil)
+	child1 := tree.NewNode(1, &[]tree.NodeIFace{child2})
+	root := tree.NewNode(0, &[]tree.NodeIFace{child1})
+	assert.ErrorIs(t, child2.TryAddChild(root), tree.ErrCycle)
+	assert.ErrorIs(t, child2.TryAddChild(child1), tree.ErrCycle)
+	assert.True(t, child2.IsLeaf())
+	assert.Equal(t, 3, root.GetSize())
+}
+
+func TestNode_TryAddChildRejectsNodeWithAnotherParent(t *testing.T) {
+	child := tree.NewNode(2, nil)
+	parent := tree.NewNode(1, &[]tree.NodeIFace{child})
+	other := tree.NewNode(0, nil)
+	assert.ErrorIs(t, other.TryAddChild(child), tree.ErrAlreadyParented)
+	assert.ErrorIs(t, parent.TryAddChild(child), tree.ErrAlreadyParented)
+	assert.Equal(t, 1, len(parent.GetChildren()))
+	assert.True(t, other.IsLeaf())
+}
+
+func TestNode_TrySetParent(t *testing.T) {
+	root := tree.NewNode(0, nil)
+	child := tree.NewNode(1, nil)
+	assert.NoError(t, child.TrySetParent(root))
+	assert.Equal(t, root, child.GetParent())
+	assert.Equal(t, []tree.NodeIFace{child}, root.GetChildren())
+	//setting the same parent again is allowed
+	assert.NoError(t, child.TrySetParent(root))
+	assert.Equal(t, 1, len(root.GetChildren()))
+	assert.NoError(t, child.TrySetParent(nil))
+	assert.Nil(t, child.GetParent())
+	assert.True(t, root.IsLeaf())
+}
+
+func TestNode_TrySetParentKeepsTreeValid(t *testing.T) {
+	root := tree.NewNode(0, nil)
+	child := tree.NewNode(1, nil)
+	//a parent link made by SetParent is completed
+	child.SetParent(root)
+	assert.NoError(t, child.TrySetParent(root))
+	assert.Equal(t, []tree.NodeIFace{child}, root.GetChildren())
+	assert.Empty(t, tree.Validate(root))
+	//and can be undone when the parent does not hold the child
+	orphan := tree.NewNode(2, nil).SetParent(root)
+	assert.NoError(t, orphan.TrySetParent(nil))
+	assert.Nil(t, orphan.GetParent())
+	assert.Empty(t, tree.Validate(root))
+}
+
+func TestNode_TrySetParentRejectsInvalidParents(t *testing.T) {
+	child2 := tree.NewNode(2, nil)
+	child1 := tree.NewNode(1, &[]tree.NodeIFace{child2})
+	root := tree.NewNode(0, &[]tree.NodeIFace{child1})
+	assert.ErrorIs(t, root.TrySetParent(root), tree.ErrSelfParent)
+	assert.ErrorIs(t, root.TrySetParent(child2), tree.ErrCycle)
+	assert.ErrorIs(t, child2.TrySetParent(root), tree.ErrAlreadyParented)
+	assert.True(t, root.IsRoot())
+	assert.Equal(t, child1, child2.GetParent())
+}
+
+func TestNode_SetChildrenReplacesExistingChildren(t *testing.T) {
+	old := tree.NewNode(1, nil)
+	root := tree.NewNode(0, &[]tree.NodeIFace{old})
+	children := []tree.NodeIFace{tree.NewNode(2, nil), tree.NewNode(3, nil)}
+	root.SetChildren(children...)
+	assert.Equal(t, children, root.GetChildren())
+	assert.Nil(t, old.GetParent())
+}
+
+func TestNode_RemoveChildIgnoresNodesThatAreNotChildren(t *testing.T) {
+	child := tree.NewNode(1, nil)
+	parent := tree.NewNode(0, &[]tree.NodeIFace{child})
+	tree.NewNode(2, nil).RemoveChild(child)
+	assert.Equal(t, parent, child.GetParent())
+	assert.Equal(t, 1, len(parent.GetChildren()))
+}
+
+func TestNode_MoveToAnotherParent(t *testing.T) {
+	a1 := tree.NewNode("a1", nil)
+	a := tree.NewNode("a", &[]tree.NodeIFace{a1})
+	b1 := tree.NewNode("b1", nil)
+	b2 := tree.NewNode("b2", nil)
+	b := tree.NewNode("b", &[]tree.NodeIFace{b1, b2})
+	root := tree.NewNode("root", &[]tree.NodeIFace{a, b})
+
+	assert.NoError(t, a1.MoveTo(b, 1))
+	assert.True(t, a.IsLeaf())
+	assert.Equal(t, []tree.NodeIFace{b1, a1, b2}, b.GetChildren())
+	assert.Equal(t, b, a1.GetParent())
+	assert.Nil(t, tree.Validate(root))
+}
+
+func TestNode_MoveToSameParent(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a, b, c})
+
+	assert.NoError(t, a.MoveTo(root, 2))
+	assert.Equal(t, []tree.NodeIFace{b, c, a}, root.GetChildren())
+	assert.NoError(t, a.MoveTo(root, 0))
+	assert.Equal(t, []tree.NodeIFace{a, b, c}, root.GetChildren())
+	assert.Nil(t, tree.Validate(root))
+}
+
+func TestNode_MoveToDetachedNode(t *testing.T) {
+	root := tree.NewNode("root", nil)
+	node := tree.NewNode("a", nil)
+	assert.NoError(t, node.MoveTo(root, 0))
+	assert.Equal(t, []tree.NodeIFace{node}, root.GetChildren())
+	assert.Equal(t, root, node.GetParent())
+}
+
+func TestNode_MoveToRejectsInvalidMoves(t *testing.T) {
+	a1 := tree.NewNode("a1", nil)
+	a := tree.NewNode("a", &[]tree.NodeIFace{a1})
+	b := tree.NewNode("b", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a, b})
+
+	assert.ErrorIs(t, a.MoveTo(nil, 0), tree.ErrNilNode)
+	assert.ErrorIs(t, a.MoveTo(a, 0), tree.ErrSelfParent)
+	assert.ErrorIs(t, a.MoveTo(a1, 0), tree.ErrCycle)
+	assert.ErrorIs(t, a.MoveTo(b, 1), tree.ErrIndexOutOfRange)
+	assert.ErrorIs(t, a.MoveTo(root, -1), tree.ErrIndexOutOfRange)
+	//nothing changed
+	assert.Equal(t, []tree.NodeIFace{a, b}, root.GetChildren())
+	assert.Equal(t, root, a.GetParent())
+	assert.Nil(t, tree.Validate(root))
+}
+
+func TestNode_RemoveChildKeepsSiblingOrder(t *testing.T) {
+	children := []tree.NodeIFace{tree.NewNode(1, nil), tree.NewNode(2, nil), tree.NewNode(3, nil), tree.NewNode(4, nil)}
+	node := tree.NewNode(0, &children).RemoveChild(children[1])
+	assert.Equal(t, []tree.NodeIFace{children[0], children[2], children[3]}, node.GetChildren())
+}
+
+func TestNode_GetSiblingsKeepsOrderAndDoesNotChangeParent(t *testing.T) {
+	children := []tree.NodeIFace{tree.NewNode(1, nil), tree.NewNode(2, nil), tree.NewNode(3, nil)}
+	root := tree.NewNode(0, &children)
+	assert.Equal(t, []tree.NodeIFace{children[1], children[2]}, children[0].GetSiblings())
+	assert.Equal(t, children, root.GetChildren())
+}
+
+func TestNode_InsertChildAt(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	root := tree.NewNode("root", nil)
+	assert.NoError(t, root.InsertChildAt(0, b))
+	assert.NoError(t, root.InsertChildAt(0, a))
+	assert.NoError(t, root.InsertChildAt(2, c))
+	assert.Equal(t, []tree.NodeIFace{a, b, c}, root.GetChildren())
+	assert.Equal(t, root, a.GetParent())
+}
+
+func TestNode_InsertChildAtRejectsInvalidInserts(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a})
+	assert.ErrorIs(t, root.InsertChildAt(2, tree.NewNode("b", nil)), tree.ErrIndexOutOfRange)
+	assert.ErrorIs(t, root.InsertChildAt(-1, tree.NewNode("b", nil)), tree.ErrIndexOutOfRange)
+	assert.ErrorIs(t, root.InsertChildAt(0, a), tree.ErrAlreadyParented)
+	assert.ErrorIs(t, a.InsertChildAt(0, root), tree.ErrCycle)
+	assert.Equal(t, []tree.NodeIFace{a}, root.GetChildren())
+}
+
+func TestNode_MoveChild(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	d := tree.NewNode("d", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a, b, c, d})
+
+	assert.NoError(t, root.MoveChild(0, 2))
+	assert.Equal(t, []tree.NodeIFace{b, c, a, d}, root.GetChildren())
+	assert.NoError(t, root.MoveChild(3, 0))
+	assert.Equal(t, []tree.NodeIFace{d, b, c, a}, root.GetChildren())
+	assert.NoError(t, root.MoveChild(1, 1))
+	assert.Equal(t, []tree.NodeIFace{d, b, c, a}, root.GetChildren())
+	assert.ErrorIs(t, root.MoveChild(0, 4), tree.ErrIndexOutOfRange)
+	assert.ErrorIs(t, root.MoveChild(-1, 0), tree.ErrIndexOutOfRange)
+}
+
+func TestNode_PositionalAccess(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a, b, c})
+
+	assert.Equal(t, b, root.GetChildAt(1))
+	assert.Nil(t, root.GetChildAt(3))
+	assert.Nil(t, root.GetChildAt(-1))
+	assert.Equal(t, a, root.FirstChild())
+	assert.Equal(t, c, root.LastChild())
+	assert.Nil(t, a.FirstChild())
+	assert.Nil(t, a.LastChild())
+
+	assert.Equal(t, -1, root.ChildIndex())
+	assert.Equal(t, 0, a.ChildIndex())
+	assert.Equal(t, 2, c.ChildIndex())
+}
+
+func TestNode_NextAndPrevSibling(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a, b, c})
+
+	assert.Equal(t, b, a.NextSibling())
+	assert.Equal(t, c, b.NextSibling())
+	assert.Nil(t, c.NextSibling())
+	assert.Equal(t, b, c.PrevSibling())
+	assert.Nil(t, a.PrevSibling())
+	assert.Nil(t, root.NextSibling())
+	assert.Nil(t, root.PrevSibling())
+}
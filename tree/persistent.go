@@ -0,0 +1,395 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"errors"
+	"sync"
+)
+
+//ErrImmutable is returned by the error only mutators of a PersistentNode, which cannot return the new version of the tree
+var ErrImmutable = errors.New("tree: persistent node cannot be changed in place")
+
+//pnode is the immutable storage of a persistent tree. A pnode is never changed once it is built, so it
+//can be shared by any number of versions of a tree
+type pnode struct {
+	value    interface{}
+	children []*pnode
+}
+
+//PersistentNode is a node of an immutable tree. Changing a PersistentNode never alters the tree it belongs to,
+//instead a new version of the tree is built that shares every unchanged subtree with the old one, and the node
+//at the same position in the new version is returned. Use Root to get the root of the new version.
+//
+//A PersistentNode is safe to use from many goroutines without locking. Nodes reached from the same root are
+//comparable, but nodes of different versions of a tree are never equal, even if they hold the same subtree.
+type PersistentNode struct {
+	NodeIFace
+	node     *pnode
+	parent   *PersistentNode
+	index    int
+	once     sync.Once
+	children []NodeIFace
+}
+
+//NewPersistentNode returns a new PersistentNode. The children are copied into the new tree
+func NewPersistentNode(v interface{}, children *[]NodeIFace) *PersistentNode {
+	pn := &pnode{value: v}
+	if children != nil {
+		pn.children = toPnodes(*children)
+	}
+	return &PersistentNode{node: pn, index: -1}
+}
+
+//ToPersistent returns a persistent copy of the tree rooted at n
+func ToPersistent(n NodeIFace) *PersistentNode {
+	return &PersistentNode{node: toPnode(n), index: -1}
+}
+
+//toPnode returns the storage for n. The storage of a PersistentNode is shared, any other tree is copied
+func toPnode(n NodeIFace) *pnode {
+	if p, ok := n.(*PersistentNode); ok {
+		return p.node
+	}
+	root := &pnode{value: n.GetValue()}
+	type copyFrame struct {
+		src NodeIFace
+		dst *pnode
+	}
+	stack := []copyFrame{{src: n, dst: root}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, c := range f.src.GetChildren() {
+			if p, ok := c.(*PersistentNode); ok {
+				f.dst.children = append(f.dst.children, p.node)
+				continue
+			}
+			pn := &pnode{value: c.GetValue()}
+			f.dst.children = append(f.dst.children, pn)
+			stack = append(stack, copyFrame{src: c, dst: pn})
+		}
+	}
+	return root
+}
+
+func toPnodes(nodes []NodeIFace) []*pnode {
+	pns := make([]*pnode, 0, len(nodes))
+	for _, c := range nodes {
+		if c != nil {
+			pns = append(pns, toPnode(c))
+		}
+	}
+	return pns
+}
+
+//Root returns the root of the version of the tree that this node belongs to
+func (n *PersistentNode) Root() *PersistentNode {
+	root := n
+	for root.parent != nil {
+		root = root.parent
+	}
+	return root
+}
+
+//replace builds a new version of the tree in which the storage of this node is pn, copying the path from this
+//node to the root, and returns the node at the position of this node in the new version
+func (n *PersistentNode) replace(pn *pnode) *PersistentNode {
+	path := n.GetAncestorsAndSelf()
+	for i := len(path) - 1; i > 0; i-- {
+		p := path[i-1].(*PersistentNode).node
+		children := make([]*pnode, len(p.children))
+		copy(children, p.children)
+		children[path[i].(*PersistentNode).index] = pn
+		pn = &pnode{value: p.value, children: children}
+	}
+	c := &PersistentNode{node: pn, index: -1}
+	for _, a := range path[1:] {
+		c = c.child(a.(*PersistentNode).index)
+	}
+	return c
+}
+
+//child returns the child at position i, which must be in range
+func (n *PersistentNode) child(i int) *PersistentNode {
+	return n.GetChildAt(i).(*PersistentNode)
+}
+
+//withChildren returns the node at the position of this node in a new version of the tree with the given children
+func (n *PersistentNode) withChildren(children []*pnode) *PersistentNode {
+	return n.replace(&pnode{value: n.node.value, children: children})
+}
+
+//SetValue returns this node in a new version of the tree in which it has the given value
+func (n *PersistentNode) SetValue(v interface{}) NodeIFace {
+	return n.replace(&pnode{value: v, children: n.node.children})
+}
+
+func (n *PersistentNode) GetValue() interface{} {
+	return n.node.value
+}
+
+//AddChild returns this node in a new version of the tree in which c is its last child.
+//c is copied into the new version, so it is not changed
+func (n *PersistentNode) AddChild(c NodeIFace) NodeIFace {
+	if c == nil {
+		return n
+	}
+	children := make([]*pnode, len(n.node.children), len(n.node.children)+1)
+	copy(children, n.node.children)
+	return n.withChildren(append(children, toPnode(c)))
+}
+
+//TryAddChild always returns ErrImmutable, use AddChild or WithChildAt
+func (n *PersistentNode) TryAddChild(NodeIFace) error {
+	return ErrImmutable
+}
+
+//InsertChildAt always returns ErrImmutable, use WithChildAt
+func (n *PersistentNode) InsertChildAt(int, NodeIFace) error {
+	return ErrImmutable
+}
+
+//MoveChild always returns ErrImmutable, use WithChildMoved
+func (n *PersistentNode) MoveChild(int, int) error {
+	return ErrImmutable
+}
+
+//WithChildAt returns this node in a new version of the tree in which c is inserted at the given position in
+//its children. Index may be between 0 and the number of children inclusive
+func (n *PersistentNode) WithChildAt(i int, c NodeIFace) (*PersistentNode, error) {
+	switch {
+	case c == nil:
+		return nil, ErrNilNode
+	case i < 0 || i > len(n.node.children):
+		return nil, ErrIndexOutOfRange
+	}
+	children := make([]*pnode, 0, len(n.node.children)+1)
+	children = append(children, n.node.children[:i]...)
+	children = append(children, toPnode(c))
+	children = append(children, n.node.children[i:]...)
+	return n.withChildren(children), nil
+}
+
+//WithChildMoved returns this node in a new version of the tree in which the child at position from is moved to
+//position to, keeping the order of the other children
+func (n *PersistentNode) WithChildMoved(from, to int) (*PersistentNode, error) {
+	size := len(n.node.children)
+	if from < 0 || from >= size || to < 0 || to >= size {
+		return nil, ErrIndexOutOfRange
+	}
+	children := make([]*pnode, size)
+	copy(children, n.node.children)
+	c := children[from]
+	if from < to {
+		copy(children[from:to], children[from+1:to+1])
+	} else {
+		copy(children[to+1:from+1], children[to:from])
+	}
+	children[to] = c
+	return n.withChildren(children), nil
+}
+
+//RemoveChild returns this node in a new version of the tree without the child c, or this node if c is not one
+//of its children. c may also be the child at the same position of this node in another version of the tree, if
+//it holds the same subtree. As a subtree may be shared by several children, c is found by its position
+func (n *PersistentNode) RemoveChild(c NodeIFace) NodeIFace {
+	p, ok := c.(*PersistentNode)
+	if !ok || p.parent == nil {
+		return n
+	}
+	i := p.index
+	if p.parent != n && (i >= len(n.node.children) || n.node.children[i] != p.node) {
+		return n
+	}
+	children := make([]*pnode, 0, len(n.node.children)-1)
+	children = append(children, n.node.children[:i]...)
+	return n.withChildren(append(children, n.node.children[i+1:]...))
+}
+
+//RemoveAllChildren returns this node in a new version of the tree in which it is a leaf
+func (n *PersistentNode) RemoveAllChildren() NodeIFace {
+	return n.withChildren(nil)
+}
+
+//GetChildren returns a copy of the children of this node
+func (n *PersistentNode) GetChildren() []NodeIFace {
+	children := n.getChildren()
+	cp := make([]NodeIFace, len(children))
+	copy(cp, children)
+	return cp
+}
+
+//getChildren returns the children of this node, creating them the first time they are asked for so that the
+//same node is always returned for the same position
+func (n *PersistentNode) getChildren() []NodeIFace {
+	n.once.Do(func() {
+		n.children = make([]NodeIFace, len(n.node.children))
+		for i, c := range n.node.children {
+			n.children[i] = &PersistentNode{node: c, parent: n, index: i}
+		}
+	})
+	return n.children
+}
+
+func (n *PersistentNode) GetChildAt(i int) NodeIFace {
+	children := n.getChildren()
+	if i < 0 || i >= len(children) {
+		return nil
+	}
+	return children[i]
+}
+
+func (n *PersistentNode) FirstChild() NodeIFace {
+	return n.GetChildAt(0)
+}
+
+func (n *PersistentNode) LastChild() NodeIFace {
+	return n.GetChildAt(len(n.node.children) - 1)
+}
+
+func (n *PersistentNode) ChildIndex() int {
+	return n.index
+}
+
+//SetChildren returns this node in a new version of the tree whose children are copies of c
+func (n *PersistentNode) SetChildren(c ...NodeIFace) NodeIFace {
+	return n.withChildren(toPnodes(c))
+}
+
+//SetParent returns this node as the root of a new tree if p is nil, otherwise this node as the last
+//child of p in a new version of the tree that p belongs to. If p is not a PersistentNode, the whole tree
+//that p belongs to is copied with ToPersistent, and p is left unchanged
+func (n *PersistentNode) SetParent(p NodeIFace) NodeIFace {
+	if p == nil {
+		return &PersistentNode{node: n.node, index: -1}
+	}
+	pp, ok := p.(*PersistentNode)
+	if !ok {
+		path := p.GetAncestorsAndSelf()
+		pp = ToPersistent(path[0])
+		for _, a := range path[1:] {
+			pp = pp.child(a.ChildIndex())
+		}
+	}
+	return pp.AddChild(n).LastChild()
+}
+
+//TrySetParent always returns ErrImmutable, use SetParent
+func (n *PersistentNode) TrySetParent(NodeIFace) error {
+	return ErrImmutable
+}
+
+//MoveTo always returns ErrImmutable, use RemoveChild and WithChildAt
+func (n *PersistentNode) MoveTo(NodeIFace, int) error {
+	return ErrImmutable
+}
+
+func (n *PersistentNode) GetParent() NodeIFace {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent
+}
+
+func (n *PersistentNode) GetAncestors() []NodeIFace {
+	parents := make([]NodeIFace, 0)
+	for p := n.parent; p != nil; p = p.parent {
+		parents = append(parents, p)
+	}
+	//reverse so that the root comes first
+	for i, j := 0, len(parents)-1; i < j; i, j = i+1, j-1 {
+		parents[i], parents[j] = parents[j], parents[i]
+	}
+	return parents
+}
+
+func (n *PersistentNode) GetAncestorsAndSelf() []NodeIFace {
+	return append(n.GetAncestors(), n)
+}
+
+func (n *PersistentNode) GetSiblings() []NodeIFace {
+	siblings := make([]NodeIFace, 0)
+	for _, v := range n.GetSiblingsAndSelf() {
+		if v != NodeIFace(n) {
+			siblings = append(siblings, v)
+		}
+	}
+	return siblings
+}
+
+func (n *PersistentNode) GetSiblingsAndSelf() []NodeIFace {
+	if n.parent == nil {
+		return []NodeIFace{n}
+	}
+	return n.parent.GetChildren()
+}
+
+func (n *PersistentNode) NextSibling() NodeIFace {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent.GetChildAt(n.index + 1)
+}
+
+func (n *PersistentNode) PrevSibling() NodeIFace {
+	if n.parent == nil {
+		return nil
+	}
+	return n.parent.GetChildAt(n.index - 1)
+}
+
+func (n *PersistentNode) IsRoot() bool {
+	return n.parent == nil
+}
+
+func (n *PersistentNode) IsChild() bool {
+	return n.parent != nil
+}
+
+func (n *PersistentNode) IsLeaf() bool {
+	return len(n.node.children) == 0
+}
+
+func (n *PersistentNode) GetDepth() int {
+	depth := 0
+	for p := n.parent; p != nil; p = p.parent {
+		depth++
+	}
+	return depth
+}
+
+func (n *PersistentNode) GetHeight() int {
+	height := 0
+	Walk(n, func(_ NodeIFace, depth int) WalkAction {
+		if depth > height {
+			height = depth
+		}
+		return WalkContinue
+	})
+	return height
+}
+
+func (n *PersistentNode) GetSize() int {
+	size := 0
+	Walk(n, func(NodeIFace, int) WalkAction {
+		size++
+		return WalkContinue
+	})
+	return size
+}
+
+func (n *PersistentNode) Accept(v VisitorIFace) interface{} {
+	return v.Visit(n)
+}
+
+//String renders the tree rooted at this node using Render with the default options
+func (n *PersistentNode) String() string {
+	return Render(n, nil)
+}
@@ -0,0 +1,173 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+)
+
+func TestPersistentNode_ImplementsNodeInterface(t *testing.T) {
+	sut := tree.NewPersistentNode("root", nil)
+	_, ok := interface{}(sut).(tree.NodeIFace)
+	assert.True(t, ok)
+}
+
+func valuesOf(nodes interface{}) []interface{} {
+	vals := make([]interface{}, 0)
+	for _, n := range nodes.([]tree.NodeIFace) {
+		vals = append(vals, n.GetValue())
+	}
+	return vals
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestPersistentNode_QueriesMatchMutableTree(t *testing.T) {
+	raw, _, _, _, _, _, _ := buildWalkTree()
+	root := tree.ToPersistent(raw)
+	assert.Equal(t, tree.Render(raw, nil), tree.Render(root, nil))
+	assert.Equal(t, tree.Render(raw, nil), root.String())
+	assert.Equal(t, 7, root.GetSize())
+	assert.Equal(t, 2, root.GetHeight())
+	assert.Equal(t, valuesOf(raw.Accept(tree.NewPostOrderVisitor())), valuesOf(root.Accept(tree.NewPostOrderVisitor())))
+
+	a := root.FirstChild()
+	d := a.FirstChild()
+	assert.Equal(t, a, root.GetChildAt(0))
+	assert.Equal(t, root, a.GetParent())
+	assert.Nil(t, root.GetParent())
+	assert.Equal(t, []tree.NodeIFace{root, a}, d.GetAncestors())
+	assert.Equal(t, []tree.NodeIFace{root, a, d}, d.GetAncestorsAndSelf())
+	assert.Equal(t, []tree.NodeIFace{root.GetChildAt(1), root.GetChildAt(2)}, a.GetSiblings())
+	assert.Equal(t, []tree.NodeIFace{root}, root.GetSiblingsAndSelf())
+	assert.Equal(t, root.GetChildAt(1), a.NextSibling())
+	assert.Nil(t, a.PrevSibling())
+	assert.Equal(t, 2, d.GetDepth())
+	assert.Equal(t, 0, a.ChildIndex())
+	assert.Equal(t, -1, root.ChildIndex())
+	assert.True(t, d.IsLeaf())
+	assert.True(t, d.IsChild())
+	assert.True(t, root.IsRoot())
+	assert.Nil(t, tree.Validate(root))
+}
+
+func TestPersistentNode_ChangesReturnNewVersionsSharingUnchangedSubtrees(t *testing.T) {
+	raw, _, _, _, _, _, _ := buildWalkTree()
+	v1 := tree.ToPersistent(raw)
+	before := tree.Render(v1, nil)
+
+	f := v1.GetChildAt(1).FirstChild()
+	newF := f.SetValue("F").(*tree.PersistentNode)
+	v2 := newF.Root()
+	assert.Equal(t, "F", newF.GetValue())
+	assert.Equal(t, "f", f.GetValue())
+	assert.Equal(t, before, tree.Render(v1, nil), "old version is unchanged")
+	assert.Equal(t, "F", v2.GetChildAt(1).FirstChild().GetValue())
+	assert.NotEqual(t, v1, v2)
+
+	v3 := v2.GetChildAt(2).AddChild(tree.NewNode("g", nil)).(*tree.PersistentNode).Root()
+	assert.Equal(t, 8, v3.GetSize())
+	assert.Equal(t, 7, v2.GetSize())
+
+	v4 := v3.RemoveChild(v1.FirstChild()).(*tree.PersistentNode)
+	assert.Equal(t, []interface{}{"b", "c"}, valuesOf(v4.GetChildren()))
+	assert.Equal(t, v4, v4.RemoveChild(tree.NewNode("x", nil)))
+	assert.Equal(t, v4, v4.RemoveChild(v1.LastChild()), "c is not at the same position in v4")
+
+	v5 := v4.SetChildren(tree.NewNode("x", nil), v1.FirstChild()).(*tree.PersistentNode)
+	assert.Equal(t, []interface{}{"root", "x", "a", "d", "e"}, valuesOf(v5.Accept(tree.NewPreOrderVisitor())))
+	assert.True(t, v5.RemoveAllChildren().IsLeaf())
+	assert.Equal(t, 5, v5.GetSize())
+}
+
+func TestPersistentNode_AddingAPersistentNodeSharesIt(t *testing.T) {
+	sub := tree.NewPersistentNode("sub", &[]tree.NodeIFace{tree.NewNode("leaf", nil)})
+	root := tree.NewPersistentNode("root", nil).AddChild(sub).AddChild(sub).(*tree.PersistentNode)
+	assert.Equal(t, 5, root.GetSize())
+	assert.NotEqual(t, root.FirstChild(), root.LastChild(), "same subtree at two positions gives two nodes")
+	assert.Equal(t, 1, root.LastChild().ChildIndex())
+	assert.Nil(t, tree.Validate(root))
+
+	removed := root.RemoveChild(root.LastChild()).(*tree.PersistentNode)
+	assert.Equal(t, 3, removed.GetSize())
+	assert.Equal(t, 1, len(removed.GetChildren()))
+	twice := removed.AddChild(tree.NewNode("other", nil)).AddChild(sub).(*tree.PersistentNode)
+	assert.Equal(t, []interface{}{"sub", "other"}, valuesOf(twice.RemoveChild(twice.LastChild()).GetChildren()),
+		"the child at the position of c is removed, not the first holding the same subtree")
+}
+
+func TestPersistentNode_WithChildAtAndWithChildMoved(t *testing.T) {
+	root := tree.NewPersistentNode("root", &[]tree.NodeIFace{tree.NewNode("a", nil), tree.NewNode("b", nil)})
+	v2, err := root.WithChildAt(1, tree.NewNode("x", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"a", "x", "b"}, valuesOf(v2.GetChildren()))
+	_, err = root.WithChildAt(3, tree.NewNode("x", nil))
+	assert.ErrorIs(t, err, tree.ErrIndexOutOfRange)
+	_, err = root.WithChildAt(0, nil)
+	assert.ErrorIs(t, err, tree.ErrNilNode)
+
+	v3, err := v2.WithChildMoved(2, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"b", "a", "x"}, valuesOf(v3.GetChildren()))
+	_, err = v2.WithChildMoved(0, 3)
+	assert.ErrorIs(t, err, tree.ErrIndexOutOfRange)
+	assert.Equal(t, []interface{}{"a", "b"}, valuesOf(root.GetChildren()))
+}
+
+func TestPersistentNode_SetParent(t *testing.T) {
+	raw, _, _, _, _, _, _ := buildWalkTree()
+	root := tree.ToPersistent(raw)
+	a := root.FirstChild()
+
+	detached := a.SetParent(nil)
+	assert.True(t, detached.IsRoot())
+	assert.Equal(t, 3, detached.GetSize())
+	assert.True(t, a.IsChild())
+
+	moved := a.SetParent(root.LastChild())
+	assert.Equal(t, "a", moved.GetValue())
+	assert.Equal(t, "c", moved.GetParent().GetValue())
+	assert.Equal(t, 10, moved.(*tree.PersistentNode).Root().GetSize())
+
+	//a mutable parent is copied with the whole of its tree
+	other, _, b, _, _, _, _ := buildWalkTree()
+	moved = a.SetParent(b)
+	assert.Equal(t, []interface{}{"root", "b", "a"}, valuesOf(moved.GetAncestorsAndSelf()))
+	assert.Equal(t, 10, moved.(*tree.PersistentNode).Root().GetSize())
+	assert.Equal(t, 7, other.GetSize(), "the mutable tree is unchanged")
+}
+
+func TestPersistentNode_ErrorOnlyMutatorsReturnErrImmutable(t *testing.T) {
+	root := tree.NewPersistentNode("root", &[]tree.NodeIFace{tree.NewNode("a", nil), tree.NewNode("b", nil)})
+	assert.ErrorIs(t, root.TryAddChild(tree.NewNode("x", nil)), tree.ErrImmutable)
+	assert.ErrorIs(t, root.InsertChildAt(0, tree.NewNode("x", nil)), tree.ErrImmutable)
+	assert.ErrorIs(t, root.MoveChild(0, 1), tree.ErrImmutable)
+	assert.ErrorIs(t, root.FirstChild().TrySetParent(nil), tree.ErrImmutable)
+	assert.ErrorIs(t, root.FirstChild().MoveTo(root.LastChild(), 0), tree.ErrImmutable)
+	assert.Equal(t, 3, root.GetSize())
+}
+
+func TestPersistentNode_SharedBetweenGoroutines(t *testing.T) {
+	raw, _, _, _, _, _, _ := buildWalkTree()
+	root := tree.ToPersistent(raw)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				v := root.GetChildAt(j % 3).AddChild(tree.NewNode(i, nil)).(*tree.PersistentNode).Root()
+				assert.Equal(t, 8, v.GetSize())
+				assert.Len(t, root.Accept(tree.NewLeafVisitor()), 4)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 7, root.GetSize())
+}
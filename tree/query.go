@@ -0,0 +1,517 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//NameFunc returns the name of a node that a query matches names against
+type NameFunc func(n NodeIFace) string
+
+//Query is a compiled path expression that selects nodes from a tree.
+//
+//An expression is a list of steps separated by / to select the children of the nodes selected so far,
+//or by // or .. to select all of their descendants. A leading / or // starts from above the root, so /root
+//selects the root if it is named root, otherwise the first step selects from the children of the root.
+//
+//Each step is a name, a quoted name, or * for any name, followed by any number of predicates in [ ].
+//The name may be left out after .. to select any name. A predicate is made of
+//
+//	depth, height, size, children (the number of), index (position in the parent)  compared with an integer
+//	name                                                                          compared with a name
+//	leaf, root                                                                    true for leaves and the root
+//
+//using the comparisons =, !=, <, <=, > and >=, combined with and, or, not and ( ). For example
+//
+//	/root/*/expenses//travel
+//	..[depth>2]
+//	//*[leaf and not name='petty cash']
+//
+//Nodes are selected in pre-order without duplicates.
+type Query struct {
+	VisitorIFace
+	expr     string
+	absolute bool
+	steps    []queryStep
+}
+
+//queryStep is one step of a compiled query
+type queryStep struct {
+	descendants bool
+	match       queryPredicate
+}
+
+//queryPredicate returns true if n is matched by a step of a query
+type queryPredicate func(n NodeIFace) bool
+
+//Compile compiles a query expression. Names are matched against name, or against FormatValue if name is nil.
+//An error wrapping ErrSyntax is returned if expr cannot be parsed
+func Compile(expr string, name NameFunc) (*Query, error) {
+	if name == nil {
+		name = FormatValue
+	}
+	p := &queryParser{lex: &queryLexer{src: []rune(expr)}, name: name}
+	q, err := p.parse()
+	if err != nil {
+		return nil, err
+	}
+	q.expr = expr
+	return q, nil
+}
+
+//MustCompile is like Compile but panics if expr cannot be parsed
+func MustCompile(expr string, name NameFunc) *Query {
+	q, err := Compile(expr, name)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+//Select compiles expr, matching names against FormatValue, and returns the nodes it selects from the tree
+//rooted at root
+func Select(root NodeIFace, expr string) ([]NodeIFace, error) {
+	q, err := Compile(expr, nil)
+	if err != nil {
+		return nil, err
+	}
+	return q.Select(root), nil
+}
+
+//String returns the expression the query was compiled from
+func (q *Query) String() string {
+	return q.expr
+}
+
+//Visit returns the []NodeIFace selected by the query from the tree rooted at n
+func (q *Query) Visit(n NodeIFace) interface{} {
+	return q.Select(n)
+}
+
+//Select returns the nodes selected by the query from the tree rooted at root
+func (q *Query) Select(root NodeIFace) []NodeIFace {
+	nodes := make([]NodeIFace, 0)
+	if root == nil {
+		return nodes
+	}
+	//a nil node stands for the document above the root, whose only child is the root
+	context := []NodeIFace{root}
+	if q.absolute {
+		context = []NodeIFace{nil}
+	}
+	var order map[NodeIFace]int
+	for _, s := range q.steps {
+		next := make([]NodeIFace, 0)
+		if s.descendants {
+			//context is in pre-order, so a node that has already been walked is below an earlier context node
+			//and its descendants have already been selected
+			walked := make(map[NodeIFace]bool)
+			for _, c := range context {
+				if walked[c] {
+					continue
+				}
+				start := c
+				if c == nil {
+					start = root
+				}
+				Walk(start, func(n NodeIFace, depth int) WalkAction {
+					walked[n] = true
+					if (c == nil || depth > 0) && s.match(n) {
+						next = append(next, n)
+					}
+					return WalkContinue
+				})
+			}
+		} else {
+			for _, c := range context {
+				children := []NodeIFace{root}
+				if c != nil {
+					children = c.GetChildren()
+				}
+				for _, ch := range children {
+					if s.match(ch) {
+						next = append(next, ch)
+					}
+				}
+			}
+			if len(context) > 1 {
+				//the children of a context node come after those of its ancestors, so put them back in pre-order
+				if order == nil {
+					order = preOrderPositions(root)
+				}
+				sort.SliceStable(next, func(i, j int) bool {
+					return order[next[i]] < order[next[j]]
+				})
+			}
+		}
+		context = next
+	}
+	return append(nodes, context...)
+}
+
+//preOrderPositions maps each node of the tree rooted at root to its position in pre-order
+func preOrderPositions(root NodeIFace) map[NodeIFace]int {
+	order := make(map[NodeIFace]int)
+	Walk(root, func(n NodeIFace, _ int) WalkAction {
+		order[n] = len(order)
+		return WalkContinue
+	})
+	return order
+}
+
+//queryToken is a lexical token of a query expression
+type queryToken struct {
+	//kind is 'w' for a word, 's' for a quoted string, 'o' for a comparison, 'D' for //, '.' for ..,
+	//0 at the end of the input or the punctuation character
+	kind rune
+	text string
+	pos  int
+}
+
+//queryLexer splits a query expression into tokens
+type queryLexer struct {
+	src []rune
+	pos int
+}
+
+func (l *queryLexer) peekRune(offset int) rune {
+	if l.pos+offset < len(l.src) {
+		return l.src[l.pos+offset]
+	}
+	return 0
+}
+
+func (l *queryLexer) next() (queryToken, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(l.src[l.pos]) {
+		l.pos++
+	}
+	tok := queryToken{pos: l.pos + 1}
+	if l.pos >= len(l.src) {
+		return tok, nil
+	}
+	r := l.src[l.pos]
+	switch {
+	case r == '/' && l.peekRune(1) == '/':
+		tok.kind = 'D'
+		l.pos += 2
+	case r == '.' && l.peekRune(1) == '.':
+		tok.kind = '.'
+		l.pos += 2
+	case strings.ContainsRune("/*[]()", r):
+		tok.kind = r
+		l.pos++
+	case r == '=':
+		tok.kind, tok.text = 'o', "="
+		l.pos++
+	case r == '!' && l.peekRune(1) == '=':
+		tok.kind, tok.text = 'o', "!="
+		l.pos += 2
+	case r == '<' || r == '>':
+		tok.kind, tok.text = 'o', string(r)
+		l.pos++
+		if l.peekRune(0) == '=' {
+			tok.text += "="
+			l.pos++
+		}
+	case r == '\'' || r == '"':
+		return l.quoted(tok, r)
+	case isQueryWordRune(r):
+		start := l.pos
+		for l.pos < len(l.src) && isQueryWordRune(l.src[l.pos]) {
+			l.pos++
+		}
+		tok.kind = 'w'
+		tok.text = string(l.src[start:l.pos])
+	default:
+		return tok, fmt.Errorf("%w: position %d: unexpected %q", ErrSyntax, tok.pos, r)
+	}
+	return tok, nil
+}
+
+func isQueryWordRune(r rune) bool {
+	return r == '_' || r == '-' || r == ':' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+//quoted reads a string quoted with q, in which \ escapes the next character
+func (l *queryLexer) quoted(tok queryToken, q rune) (queryToken, error) {
+	b := new(strings.Builder)
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		r := l.src[l.pos]
+		switch {
+		case r == q:
+			l.pos++
+			tok.kind, tok.text = 's', b.String()
+			return tok, nil
+		case r == '\\' && l.pos+1 < len(l.src):
+			l.pos++
+			b.WriteRune(l.src[l.pos])
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return tok, fmt.Errorf("%w: position %d: unterminated string", ErrSyntax, tok.pos)
+}
+
+//queryParser parses a query expression into a Query
+type queryParser struct {
+	lex  *queryLexer
+	tok  queryToken
+	name NameFunc
+}
+
+func (p *queryParser) advance() error {
+	tok, err := p.lex.next()
+	p.tok = tok
+	return err
+}
+
+func (p *queryParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: position %d: %s", ErrSyntax, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *queryParser) expect(kind rune) error {
+	if p.tok.kind != kind {
+		return p.errorf("expected %q", kind)
+	}
+	return p.advance()
+}
+
+//keyword returns true if the current token is the given keyword
+func (p *queryParser) keyword(kw string) bool {
+	return p.tok.kind == 'w' && p.tok.text == kw
+}
+
+func (p *queryParser) parse() (*Query, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	q := new(Query)
+	descendants, anyName := false, false
+	switch p.tok.kind {
+	case '/', 'D':
+		q.absolute = true
+		descendants = p.tok.kind == 'D'
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	case '.':
+		descendants, anyName = true, true
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	for {
+		s, err := p.step(descendants, anyName)
+		if err != nil {
+			return nil, err
+		}
+		q.steps = append(q.steps, s)
+		switch p.tok.kind {
+		case 0:
+			return q, nil
+		case '/', 'D', '.':
+			descendants, anyName = p.tok.kind != '/', p.tok.kind == '.'
+		default:
+			return nil, p.errorf("expected /, //, .. or end of query")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+//step parses a name test and its predicates. anyName is true if the name may be left out
+func (p *queryParser) step(descendants, anyName bool) (queryStep, error) {
+	s := queryStep{descendants: descendants}
+	matches := make([]queryPredicate, 0)
+	switch {
+	case p.tok.kind == '*':
+		if err := p.advance(); err != nil {
+			return s, err
+		}
+	case p.tok.kind == 'w' || p.tok.kind == 's':
+		name := p.tok.text
+		matches = append(matches, func(n NodeIFace) bool {
+			return p.name(n) == name
+		})
+		if err := p.advance(); err != nil {
+			return s, err
+		}
+	case !anyName || p.tok.kind != '[' && p.tok.kind != 0:
+		return s, p.errorf("expected name or *")
+	}
+	for p.tok.kind == '[' {
+		if err := p.advance(); err != nil {
+			return s, err
+		}
+		pred, err := p.or()
+		if err != nil {
+			return s, err
+		}
+		if err := p.expect(']'); err != nil {
+			return s, err
+		}
+		matches = append(matches, pred)
+	}
+	s.match = func(n NodeIFace) bool {
+		for _, m := range matches {
+			if !m(n) {
+				return false
+			}
+		}
+		return true
+	}
+	return s, nil
+}
+
+func (p *queryParser) or() (queryPredicate, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n NodeIFace) bool { return l(n) || right(n) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) and() (queryPredicate, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(n NodeIFace) bool { return l(n) && right(n) }
+	}
+	return left, nil
+}
+
+func (p *queryParser) unary() (queryPredicate, error) {
+	switch {
+	case p.keyword("not"):
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		pred, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n NodeIFace) bool { return !pred(n) }, nil
+	case p.tok.kind == '(':
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		pred, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return pred, p.expect(')')
+	case p.keyword("leaf"):
+		return func(n NodeIFace) bool { return n.IsLeaf() }, p.advance()
+	case p.keyword("root"):
+		return func(n NodeIFace) bool { return n.IsRoot() }, p.advance()
+	case p.keyword("name"):
+		return p.comparison(func(op string, lit queryToken) (queryPredicate, error) {
+			if lit.kind != 'w' && lit.kind != 's' {
+				return nil, p.errorf("expected name")
+			}
+			cmp := comparisonFunc(op)
+			return func(n NodeIFace) bool { return cmp(strings.Compare(p.name(n), lit.text)) }, nil
+		})
+	}
+	var attr func(NodeIFace) int
+	switch {
+	case p.keyword("depth"):
+		attr = NodeIFace.GetDepth
+	case p.keyword("height"):
+		attr = NodeIFace.GetHeight
+	case p.keyword("size"):
+		attr = NodeIFace.GetSize
+	case p.keyword("children"):
+		attr = func(n NodeIFace) int { return len(n.GetChildren()) }
+	case p.keyword("index"):
+		attr = NodeIFace.ChildIndex
+	default:
+		return nil, p.errorf("expected predicate")
+	}
+	return p.comparison(func(op string, lit queryToken) (queryPredicate, error) {
+		v, err := strconv.Atoi(lit.text)
+		if lit.kind != 'w' || err != nil {
+			return nil, p.errorf("expected integer")
+		}
+		cmp := comparisonFunc(op)
+		return func(n NodeIFace) bool {
+			a := attr(n)
+			switch {
+			case a < v:
+				return cmp(-1)
+			case a > v:
+				return cmp(1)
+			}
+			return cmp(0)
+		}, nil
+	})
+}
+
+//comparison parses the comparison and literal following an attribute and builds the predicate with build
+func (p *queryParser) comparison(build func(op string, lit queryToken) (queryPredicate, error)) (queryPredicate, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind != 'o' {
+		return nil, p.errorf("expected comparison")
+	}
+	op := p.tok.text
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	pred, err := build(op, p.tok)
+	if err != nil {
+		return nil, err
+	}
+	return pred, p.advance()
+}
+
+//comparisonFunc returns a function that tests the result of a three way comparison against op
+func comparisonFunc(op string) func(c int) bool {
+	switch op {
+	case "!=":
+		return func(c int) bool { return c != 0 }
+	case "<":
+		return func(c int) bool { return c < 0 }
+	case "<=":
+		return func(c int) bool { return c <= 0 }
+	case ">":
+		return func(c int) bool { return c > 0 }
+	case ">=":
+		return func(c int) bool { return c >= 0 }
+	}
+	return func(c int) bool { return c == 0 }
+}
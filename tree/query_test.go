@@ -0,0 +1,127 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestQuery_ImplementsVisitorInterface(t *testing.T) {
+	sut := tree.MustCompile("*", nil)
+	_, ok := interface{}(sut).(tree.VisitorIFace)
+	assert.True(t, ok)
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestSelect(t *testing.T) {
+	root, a, b, c, d, e, f := buildWalkTree()
+	tests := []struct {
+		expr     string
+		expected []tree.NodeIFace
+	}{
+		{"/root", []tree.NodeIFace{root}},
+		{"/a", []tree.NodeIFace{}},
+		{"a", []tree.NodeIFace{a}},
+		{"*", []tree.NodeIFace{a, b, c}},
+		{"/root/*", []tree.NodeIFace{a, b, c}},
+		{"/root/*/*", []tree.NodeIFace{d, e, f}},
+		{"/root/a/e", []tree.NodeIFace{e}},
+		{"//*", []tree.NodeIFace{root, a, d, e, b, f, c}},
+		{"//*[leaf]", []tree.NodeIFace{d, e, f, c}},
+		{"//f", []tree.NodeIFace{f}},
+		{"/root//f", []tree.NodeIFace{f}},
+		{"..", []tree.NodeIFace{a, d, e, b, f, c}},
+		{"..[depth>1]", []tree.NodeIFace{d, e, f}},
+		{"..e", []tree.NodeIFace{e}},
+		{"a..", []tree.NodeIFace{d, e}},
+		{"//*[root]", []tree.NodeIFace{root}},
+		{"//*[children=2]", []tree.NodeIFace{a}},
+		{"//*[size>=2 and not root]", []tree.NodeIFace{a, b}},
+		{"//*[height=1]", []tree.NodeIFace{a, b}},
+		{"*[index=0 or index=2]", []tree.NodeIFace{a, c}},
+		{"*[index!=1]", []tree.NodeIFace{a, c}},
+		{"*[index<1]", []tree.NodeIFace{a}},
+		{"*[index<=1]", []tree.NodeIFace{a, b}},
+		{"*[index>1]", []tree.NodeIFace{c}},
+		{"//*[name='e']", []tree.NodeIFace{e}},
+		{"//*[name>=d and leaf]", []tree.NodeIFace{d, e, f}},
+		{"//*[not (leaf or root)]", []tree.NodeIFace{a, b}},
+		{"//*[leaf][depth=1]", []tree.NodeIFace{c}},
+		{` / root / "b" / * `, []tree.NodeIFace{f}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			nodes, err := tree.Select(root, tt.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, nodes)
+		})
+	}
+}
+
+func TestSelect_DocumentOrderWithoutDuplicates(t *testing.T) {
+	root, a, _, _, d, e, _ := buildWalkTree()
+	//a second a below d
+	aa := tree.NewNode("a", nil)
+	d.AddChild(aa)
+	g := tree.NewNode("g", nil)
+	aa.AddChild(g)
+
+	nodes, err := tree.Select(root, "//a//*")
+	assert.NoError(t, err)
+	assert.Equal(t, []tree.NodeIFace{d, aa, g, e}, nodes)
+
+	nodes, err = tree.Select(root, "//a/*")
+	assert.NoError(t, err)
+	assert.Equal(t, []tree.NodeIFace{d, g, e}, nodes)
+
+	nodes, err = tree.Select(root, "//a")
+	assert.NoError(t, err)
+	assert.Equal(t, []tree.NodeIFace{a, aa}, nodes)
+}
+
+func TestCompile_NameFunc(t *testing.T) {
+	root, _, b, _, _, _, _ := buildWalkTree()
+	sut, err := tree.Compile("/ROOT/B", func(n tree.NodeIFace) string {
+		return strings.ToUpper(n.GetValue().(string))
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/ROOT/B", sut.String())
+	assert.Equal(t, []tree.NodeIFace{b}, sut.Select(root))
+	assert.Equal(t, []tree.NodeIFace{b}, root.Accept(sut))
+	assert.Equal(t, []tree.NodeIFace{}, sut.Select(nil))
+}
+
+func TestCompile_SyntaxErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"/",
+		"a/",
+		"a//",
+		"a b",
+		"a[",
+		"a[]",
+		"a[depth]",
+		"a[depth>x]",
+		"a[depth>'1']",
+		"a[name>*]",
+		"a[foo=1]",
+		"a[(leaf]",
+		"a[leaf and]",
+		"'a",
+		"a?",
+		"../a",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := tree.Compile(expr, nil)
+			assert.ErrorIs(t, err, tree.ErrSyntax)
+		})
+	}
+	assert.Panics(t, func() { tree.MustCompile("[", nil) })
+}
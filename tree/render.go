@@ -0,0 +1,141 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"fmt"
+	"strings"
+)
+
+//RenderOptions controls how Render draws a tree
+type RenderOptions struct {
+	//ASCII draws the connectors with plain ASCII characters rather than box drawing characters
+	ASCII bool
+	//Format returns the text drawn for a node, with line breaks replaced by spaces. Defaults to the node value
+	//formatted with fmt.Sprint
+	Format func(NodeIFace) string
+	//MaxDepth is the deepest level drawn below the root, deeper nodes are replaced by a count. 0 draws all levels
+	MaxDepth int
+	//ShowDepth annotates each node with its depth
+	ShowDepth bool
+	//ShowSize annotates each node with the size of the tree rooted at it
+	ShowSize bool
+}
+
+//connectors used to draw a tree: branch, last branch, continuation, gap and truncation
+var (
+	unicodeConnectors = [5]string{"├── ", "└── ", "│   ", "    ", "…"}
+	asciiConnectors   = [5]string{"|-- ", "`-- ", "|   ", "    ", "..."}
+)
+
+//Render draws the tree rooted at root in the style of the tree(1) command, one node per line.
+//opts may be nil to use the defaults
+func Render(root NodeIFace, opts *RenderOptions) string {
+	if opts == nil {
+		opts = &RenderOptions{}
+	}
+	connectors := unicodeConnectors
+	if opts.ASCII {
+		connectors = asciiConnectors
+	}
+	format := opts.Format
+	if format == nil {
+		format = FormatValue
+	}
+	var sizes map[NodeIFace]int
+	if opts.ShowSize || opts.MaxDepth > 0 {
+		sizes = subtreeSizes(root)
+	}
+	rootDepth := root.GetDepth()
+
+	type frame struct {
+		node  NodeIFace
+		depth int
+		last  bool
+	}
+	b := new(strings.Builder)
+	//lastAt records, for each depth on the current path, whether the node at that depth is the last child
+	lastAt := make([]bool, 0)
+	stack := []frame{{node: root, last: true}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		lastAt = append(lastAt[:f.depth], f.last)
+
+		writePrefix(b, lastAt, f.depth, connectors)
+		b.WriteString(singleLine(format(f.node)))
+		switch {
+		case opts.ShowDepth && opts.ShowSize:
+			fmt.Fprintf(b, " [depth:%d size:%d]", rootDepth+f.depth, sizes[f.node])
+		case opts.ShowDepth:
+			fmt.Fprintf(b, " [depth:%d]", rootDepth+f.depth)
+		case opts.ShowSize:
+			fmt.Fprintf(b, " [size:%d]", sizes[f.node])
+		}
+		b.WriteString("\n")
+
+		children := f.node.GetChildren()
+		if len(children) == 0 {
+			continue
+		}
+		if opts.MaxDepth > 0 && f.depth == opts.MaxDepth {
+			lastAt = append(lastAt, true)
+			writePrefix(b, lastAt, f.depth+1, connectors)
+			fmt.Fprintf(b, "%s (%d more)\n", connectors[4], sizes[f.node]-1)
+			continue
+		}
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, frame{node: children[i], depth: f.depth + 1, last: i == len(children)-1})
+		}
+	}
+	return b.String()
+}
+
+//writePrefix writes the connectors drawn in front of a node at depth
+func writePrefix(b *strings.Builder, lastAt []bool, depth int, connectors [5]string) {
+	if depth == 0 {
+		return
+	}
+	for i := 1; i < depth; i++ {
+		if lastAt[i] {
+			b.WriteString(connectors[3])
+		} else {
+			b.WriteString(connectors[2])
+		}
+	}
+	if lastAt[depth] {
+		b.WriteString(connectors[1])
+	} else {
+		b.WriteString(connectors[0])
+	}
+}
+
+//subtreeSizes returns the size of the tree rooted at each node of the tree rooted at root
+func subtreeSizes(root NodeIFace) map[NodeIFace]int {
+	sizes := make(map[NodeIFace]int)
+	WalkPostOrder(root, func(n NodeIFace, _ int) WalkAction {
+		size := 1
+		for _, c := range n.GetChildren() {
+			size += sizes[c]
+		}
+		sizes[n] = size
+		return WalkContinue
+	})
+	return sizes
+}
+
+//FormatValue returns the value of a node formatted with fmt.Sprint
+func FormatValue(n NodeIFace) string {
+	return fmt.Sprint(n.GetValue())
+}
+
+//String returns the tree rooted at this node drawn by Render with the default options
+func (n *Node) String() string {
+	return Render(n, nil)
+}
@@ -0,0 +1,101 @@
+package tree_test
+
+import (
+	"fmt"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestRender_Unicode(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	expected := `root
+├── a
+│   ├── d
+│   └── e
+├── b
+│   └── f
+└── c
+`
+	assert.Equal(t, expected, tree.Render(root, nil))
+}
+
+func TestRender_ASCII(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	expected := "root\n" +
+		"|-- a\n" +
+		"|   |-- d\n" +
+		"|   `-- e\n" +
+		"|-- b\n" +
+		"|   `-- f\n" +
+		"`-- c\n"
+	assert.Equal(t, expected, tree.Render(root, &tree.RenderOptions{ASCII: true}))
+}
+
+func TestRender_LastChildWithChildren(t *testing.T) {
+	root, _, _, c, _, _, _ := buildWalkTree()
+	c.AddChild(tree.NewNode("g", nil))
+	expected := `root
+├── a
+│   ├── d
+│   └── e
+├── b
+│   └── f
+└── c
+    └── g
+`
+	assert.Equal(t, expected, tree.Render(root, nil))
+}
+
+func TestRender_Format(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	actual := tree.Render(root, &tree.RenderOptions{Format: func(n tree.NodeIFace) string {
+		return strings.ToUpper(n.GetValue().(string))
+	}})
+	assert.True(t, strings.HasPrefix(actual, "ROOT\n├── A\n"))
+}
+
+func TestRender_MultiLineValues(t *testing.T) {
+	root, a, _, _, _, _, _ := buildWalkTree()
+	a.SetValue("we\"ird\\n\nlabel")
+	a.FirstChild().SetValue("two\r\nlines")
+	assert.True(t, strings.HasPrefix(tree.Render(root, nil), "root\n├── we\"ird\\n label\n│   ├── two lines\n│   └── e\n"))
+}
+
+func TestRender_MaxDepth(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	expected := `root
+├── a
+│   └── … (2 more)
+├── b
+│   └── … (1 more)
+└── c
+`
+	assert.Equal(t, expected, tree.Render(root, &tree.RenderOptions{MaxDepth: 1}))
+	chain, _ := buildChain(4)
+	assert.Equal(t, "0\n`-- 1\n    `-- ... (2 more)\n", tree.Render(chain, &tree.RenderOptions{MaxDepth: 1, ASCII: true}))
+}
+
+func TestRender_Annotations(t *testing.T) {
+	root, a, _, _, _, _, _ := buildWalkTree()
+	expected := `a [depth:1 size:3]
+├── d [depth:2 size:1]
+└── e [depth:2 size:1]
+`
+	assert.Equal(t, expected, tree.Render(a, &tree.RenderOptions{ShowDepth: true, ShowSize: true}))
+	assert.True(t, strings.HasPrefix(tree.Render(root, &tree.RenderOptions{ShowDepth: true}), "root [depth:0]\n├── a [depth:1]\n"))
+	assert.True(t, strings.HasPrefix(tree.Render(root, &tree.RenderOptions{ShowSize: true}), "root [size:7]\n├── a [size:3]\n"))
+}
+
+func TestNode_String(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	assert.Equal(t, tree.Render(root, nil), fmt.Sprint(root))
+}
@@ -0,0 +1,241 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "fmt"
+
+//TxTree stages changes to a tree of Nodes. Nothing is changed until the function given to Tx returns without error
+type TxTree struct {
+	root NodeIFace
+	ops  []txOp
+	//touched are the nodes given to the staged changes, whose links are checked before the changes are kept
+	touched []NodeIFace
+	err     error
+}
+
+//txOp is a staged change. do makes the change, which is recorded by observing watch
+type txOp struct {
+	watch *Node
+	do    func() error
+}
+
+//Tx calls fn to stage changes to the tree rooted at root, then makes them in the order they were staged.
+//If fn returns an error or panics nothing is changed. If a change fails, or afterwards the tree fails Validate or
+//has a node whose parent does not hold it as a child, every change already made is undone and the error returned.
+//A panic while the changes are made is raised again once they are undone.
+//
+//Listeners see each change as it is made, and as it is undone. All the nodes given to the TxTree must be *Node
+func Tx(root NodeIFace, fn func(tx *TxTree) error) error {
+	tx := &TxTree{
+		root:    root,
+		ops:     make([]txOp, 0),
+		touched: []NodeIFace{root},
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if tx.err != nil {
+		return tx.err
+	}
+	return tx.commit()
+}
+
+//Root returns the root of the tree
+func (tx *TxTree) Root() NodeIFace {
+	return tx.root
+}
+
+//stage adds a change made by calling do on watch, the node whose observers are told of the change
+func (tx *TxTree) stage(watch NodeIFace, do func() error, nodes ...NodeIFace) {
+	if tx.err != nil {
+		return
+	}
+	if watch == nil {
+		tx.err = fmt.Errorf("%w: change %d", ErrNilNode, len(tx.ops))
+		return
+	}
+	wn, ok := watch.(*Node)
+	if !ok {
+		tx.err = fmt.Errorf("%w: change %d: %T", ErrUnsupportedNode, len(tx.ops), watch)
+		return
+	}
+	touched := []NodeIFace{watch}
+	for _, n := range nodes {
+		if n == nil {
+			continue
+		}
+		if _, ok := n.(*Node); !ok {
+			tx.err = fmt.Errorf("%w: change %d: %T", ErrUnsupportedNode, len(tx.ops), n)
+			return
+		}
+		touched = append(touched, n)
+	}
+	tx.ops = append(tx.ops, txOp{watch: wn, do: do})
+	tx.touched = append(tx.touched, touched...)
+}
+
+//SetValue stages n.SetValue(v)
+func (tx *TxTree) SetValue(n NodeIFace, v interface{}) {
+	tx.stage(n, func() error {
+		n.SetValue(v)
+		return nil
+	})
+}
+
+//AddChild stages p.TryAddChild(c)
+func (tx *TxTree) AddChild(p, c NodeIFace) {
+	tx.stage(p, func() error {
+		return p.TryAddChild(c)
+	}, c)
+}
+
+//InsertChildAt stages p.InsertChildAt(i, c)
+func (tx *TxTree) InsertChildAt(p NodeIFace, i int, c NodeIFace) {
+	tx.stage(p, func() error {
+		return p.InsertChildAt(i, c)
+	}, c)
+}
+
+//RemoveChild stages p.RemoveChild(c)
+func (tx *TxTree) RemoveChild(p, c NodeIFace) {
+	tx.stage(p, func() error {
+		p.RemoveChild(c)
+		return nil
+	}, c)
+}
+
+//RemoveAllChildren stages p.RemoveAllChildren()
+func (tx *TxTree) RemoveAllChildren(p NodeIFace) {
+	tx.stage(p, func() error {
+		p.RemoveAllChildren()
+		return nil
+	})
+}
+
+//SetChildren stages p.SetChildren(c...). The change fails if a child is nil, p or an ancestor of p, or has a
+//parent other than p
+func (tx *TxTree) SetChildren(p NodeIFace, c ...NodeIFace) {
+	tx.stage(p, func() error {
+		for _, cc := range c {
+			if cc != nil && cc.GetParent() == p {
+				continue
+			}
+			if err := p.(*Node).checkChild(cc); err != nil {
+				return err
+			}
+		}
+		p.SetChildren(c...)
+		return nil
+	}, c...)
+}
+
+//MoveChild stages p.MoveChild(from, to)
+func (tx *TxTree) MoveChild(p NodeIFace, from, to int) {
+	tx.stage(p, func() error {
+		return p.MoveChild(from, to)
+	})
+}
+
+//MoveTo stages n.MoveTo(p, index)
+func (tx *TxTree) MoveTo(n, p NodeIFace, index int) {
+	if p == nil {
+		tx.stage(nil, nil, n)
+		return
+	}
+	tx.stage(p, func() error {
+		return n.MoveTo(p, index)
+	}, n)
+}
+
+//SetParent stages n.SetParent(p)
+func (tx *TxTree) SetParent(n, p NodeIFace) {
+	tx.stage(n, func() error {
+		n.SetParent(p)
+		return nil
+	}, p)
+}
+
+//commit makes the staged changes, undoing them if any fails or the tree is left broken
+func (tx *TxTree) commit() error {
+	rec := &txObserver{events: make([]Event, 0)}
+	watched := make(map[*Node]bool)
+	for _, op := range tx.ops {
+		if !watched[op.watch] {
+			watched[op.watch] = true
+			op.watch.observe(rec)
+		}
+	}
+	committed := false
+	defer func() {
+		for n := range watched {
+			n.unobserve(rec)
+		}
+		if !committed {
+			tx.rollback(rec.events)
+		}
+	}()
+	for i, op := range tx.ops {
+		if err := op.do(); err != nil {
+			return fmt.Errorf("%w: change %d", err, i)
+		}
+	}
+	if err := tx.validate(); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+//rollback undoes the events, most recent first
+func (tx *TxTree) rollback(events []Event) {
+	for i := len(events) - 1; i >= 0; i-- {
+		applyEvent(invertEvent(events[i]))
+	}
+}
+
+//validate returns an InvariantError if a node given to the staged changes is its own ancestor or has a parent that
+//does not hold it as a child, or if the tree holding it fails Validate
+func (tx *TxTree) validate() error {
+	checked := make(map[NodeIFace]bool)
+	for _, n := range tx.touched {
+		seen := map[NodeIFace]bool{n: true}
+		top := n
+		for p := n.GetParent(); p != nil; p = p.GetParent() {
+			if seen[p] {
+				return &InvariantError{Node: n, Parent: n.GetParent(), Err: ErrCycle}
+			}
+			seen[p] = true
+			top = p
+		}
+		if p := n.GetParent(); p != nil && !containsNode(p.GetChildren(), n) {
+			return &InvariantError{Node: n, Parent: p, Err: ErrInconsistentParent}
+		}
+		if checked[top] {
+			continue
+		}
+		checked[top] = true
+		if errs := Validate(top); len(errs) > 0 {
+			return errs[0]
+		}
+	}
+	return nil
+}
+
+//txObserver records the changes made by a transaction
+type txObserver struct {
+	events []Event
+}
+
+func (o *txObserver) checkAdd(_, _ NodeIFace) error {
+	return nil
+}
+
+func (o *txObserver) notify(e Event) {
+	o.events = append(o.events, e)
+}
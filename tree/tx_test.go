@@ -0,0 +1,176 @@
+package tree_test
+
+import (
+	"errors"
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestTx_CommitsStagedChanges(t *testing.T) {
+	root, a, b, c, d, e, f := buildWalkTree()
+	g := tree.NewNode("g", nil)
+	events, unsubscribe := record(root)
+	defer unsubscribe()
+
+	err := tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.SetValue(d, "D")
+		tx.AddChild(c, g)
+		tx.MoveTo(e, b, 0)
+		tx.RemoveChild(b, f)
+		assert.Empty(t, *events, "nothing is changed while staging")
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "root\n├── a\n│   └── D\n├── b\n│   └── e\n└── c\n    └── g\n", tree.Render(root, nil))
+	assert.Len(t, *events, 4)
+	assert.Nil(t, f.GetParent())
+	assert.Equal(t, a, d.GetParent())
+}
+
+func TestTx_NothingChangedOnError(t *testing.T) {
+	root, _, b, _, d, _, _ := buildWalkTree()
+	before := tree.Render(root, nil)
+	events, unsubscribe := record(root)
+	defer unsubscribe()
+	errImport := errors.New("bad row")
+
+	err := tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.SetValue(d, "D")
+		tx.RemoveAllChildren(b)
+		return errImport
+	})
+
+	assert.Equal(t, errImport, err)
+	assert.Equal(t, before, tree.Render(root, nil))
+	assert.Empty(t, *events)
+}
+
+func TestTx_NothingChangedOnPanic(t *testing.T) {
+	root, _, _, _, d, _, _ := buildWalkTree()
+	before := tree.Render(root, nil)
+
+	assert.PanicsWithValue(t, "bad row", func() {
+		_ = tree.Tx(root, func(tx *tree.TxTree) error {
+			tx.SetValue(d, "D")
+			panic("bad row")
+		})
+	})
+	assert.Equal(t, before, tree.Render(root, nil))
+}
+
+func TestTx_RollsBackWhenAChangeFails(t *testing.T) {
+	root, a, b, c, d, e, f := buildWalkTree()
+	all := []tree.NodeIFace{root, a, b, c, d, e, f}
+	g := tree.NewNode("g", nil)
+	before := snapshot(all)
+
+	err := tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.SetValue(d, "D")
+		tx.SetChildren(b, g)
+		tx.MoveTo(e, c, 0)
+		tx.MoveChild(a, 0, 0)
+		tx.RemoveAllChildren(root)
+		tx.AddChild(g, a)
+		tx.MoveTo(a, d, 0) //d is below a
+		return nil
+	})
+
+	assert.True(t, errors.Is(err, tree.ErrCycle))
+	assert.Equal(t, before, snapshot(all))
+	assert.Nil(t, g.GetParent())
+	assert.Empty(t, g.GetChildren())
+	assert.Empty(t, tree.Validate(root))
+}
+
+func TestTx_ValidatesBeforeCommit(t *testing.T) {
+	root, a, _, c, _, _, _ := buildWalkTree()
+	before := tree.Render(root, nil)
+	x := tree.NewNode("x", nil)
+
+	err := tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.SetValue(a, "A")
+		tx.SetParent(x, c)
+		return nil
+	})
+	assert.True(t, errors.Is(err, tree.ErrInconsistentParent))
+	assert.Equal(t, before, tree.Render(root, nil))
+	assert.Nil(t, x.GetParent())
+
+	err = tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.SetParent(root, c)
+		return nil
+	})
+	assert.True(t, errors.Is(err, tree.ErrCycle))
+	assert.Nil(t, root.GetParent())
+
+	err = tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.SetChildren(c, a)
+		return nil
+	})
+	assert.True(t, errors.Is(err, tree.ErrAlreadyParented))
+	assert.Equal(t, before, tree.Render(root, nil))
+}
+
+func TestTx_PanicWhileCommittingIsRaisedAfterRollback(t *testing.T) {
+	root, a, _, _, d, _, _ := buildWalkTree()
+	before := tree.Render(root, nil)
+	unsubscribe := root.(*tree.Node).Subscribe(func(e tree.Event) {
+		if v, ok := e.(tree.ValueChanged); ok && v.NewValue == "boom" {
+			panic("listener failed")
+		}
+	})
+	defer unsubscribe()
+
+	assert.PanicsWithValue(t, "listener failed", func() {
+		_ = tree.Tx(root, func(tx *tree.TxTree) error {
+			tx.RemoveChild(a, d)
+			tx.SetValue(a, "boom")
+			return nil
+		})
+	})
+	assert.Equal(t, before, tree.Render(root, nil))
+}
+
+func TestTx_RollbackKeepsIndexInSync(t *testing.T) {
+	root, _, b, c, _, _, _ := buildWalkTree()
+	x, err := tree.NewTreeIndex(root, valueKey)
+	assert.NoError(t, err)
+	defer x.Close()
+
+	err = tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.AddChild(c, tree.NewNode("g", nil))
+		tx.RemoveAllChildren(b)
+		tx.AddChild(c, tree.NewNode("d", nil))
+		return nil
+	})
+	assert.True(t, errors.Is(err, tree.ErrDuplicateID))
+	assert.False(t, x.Contains("g"))
+	assert.True(t, x.Contains("f"))
+	assert.Equal(t, 7, x.Len())
+}
+
+func TestTx_NeedsNodes(t *testing.T) {
+	root, a, _, _, _, _, _ := buildWalkTree()
+	p := tree.NewPersistentNode("p", nil)
+
+	err := tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.AddChild(a, p)
+		return nil
+	})
+	assert.True(t, errors.Is(err, tree.ErrUnsupportedNode))
+
+	err = tree.Tx(root, func(tx *tree.TxTree) error {
+		tx.MoveTo(a, nil, 0)
+		return nil
+	})
+	assert.True(t, errors.Is(err, tree.ErrNilNode))
+}
@@ -0,0 +1,86 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import "fmt"
+
+//InvariantError reports a node whose parent and children links are inconsistent
+type InvariantError struct {
+	//Node is the offending node
+	Node NodeIFace
+	//Parent is the node whose children contain Node, or the parent of Node if Node is the validated root
+	Parent NodeIFace
+	//Err is the broken invariant, one of ErrNilNode, ErrInconsistentParent, ErrAlreadyParented or ErrCycle
+	Err error
+}
+
+func (e *InvariantError) Error() string {
+	if e.Node == nil {
+		return fmt.Sprintf("%v: child of %v", e.Err, e.Parent.GetValue())
+	}
+	return fmt.Sprintf("%v: node %v", e.Err, e.Node.GetValue())
+}
+
+func (e *InvariantError) Unwrap() error {
+	return e.Err
+}
+
+//Validate checks that every parent link in the tree rooted at root agrees with the children of
+//that parent, and that no node appears more than once. It returns nil if the tree is consistent.
+func Validate(root NodeIFace) []*InvariantError {
+	errs := make([]*InvariantError, 0)
+	if p := root.GetParent(); p != nil && !containsNode(p.GetChildren(), root) {
+		errs = append(errs, &InvariantError{Node: root, Parent: p, Err: ErrInconsistentParent})
+	}
+	//the stack holds the path from the root to the node being checked
+	stack := []walkFrame{{node: root}}
+	visited := map[NodeIFace]bool{root: true}
+	path := map[NodeIFace]bool{root: true}
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		n := stack[top].node
+		children := n.GetChildren()
+		if stack[top].next == len(children) {
+			delete(path, n)
+			stack = stack[:top]
+			continue
+		}
+		c := children[stack[top].next]
+		stack[top].next++
+		switch {
+		case c == nil:
+			errs = append(errs, &InvariantError{Parent: n, Err: ErrNilNode})
+			continue
+		case path[c]:
+			errs = append(errs, &InvariantError{Node: c, Parent: n, Err: ErrCycle})
+			continue
+		case visited[c]:
+			errs = append(errs, &InvariantError{Node: c, Parent: n, Err: ErrAlreadyParented})
+			continue
+		case c.GetParent() != n:
+			errs = append(errs, &InvariantError{Node: c, Parent: n, Err: ErrInconsistentParent})
+		}
+		visited[c] = true
+		path[c] = true
+		stack = append(stack, walkFrame{node: c})
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func containsNode(nodes []NodeIFace, n NodeIFace) bool {
+	for _, c := range nodes {
+		if c == n {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestValidate_ConsistentTree(t *testing.T) {
+	root := tree.NewNode("root", nil)
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	root = root.AddChild(a).AddChild(b).AddChild(c)
+	a = a.AddChild(tree.NewNode("d", nil)).AddChild(tree.NewNode("e", nil))
+	b = b.AddChild(tree.NewNode("f", nil))
+	assert.Nil(t, tree.Validate(root))
+	assert.Nil(t, tree.Validate(a))
+}
+
+func TestValidate_ReportsParentThatIsNotInChildren(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a})
+	other := tree.NewNode("other", nil)
+	a.SetParent(other)
+
+	errs := tree.Validate(root)
+	assert.Equal(t, 1, len(errs))
+	assert.ErrorIs(t, errs[0], tree.ErrInconsistentParent)
+	assert.Equal(t, a, errs[0].Node)
+	assert.Equal(t, root, errs[0].Parent)
+
+	errs = tree.Validate(a)
+	assert.Equal(t, 1, len(errs))
+	assert.ErrorIs(t, errs[0], tree.ErrInconsistentParent)
+	assert.Equal(t, other, errs[0].Parent)
+}
+
+func TestValidate_ReportsNodeWithTwoParents(t *testing.T) {
+	shared := tree.NewNode("shared", nil)
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a, b})
+	a.AddChild(shared)
+	b.AddChild(shared)
+
+	errs := tree.Validate(root)
+	assert.Equal(t, 2, len(errs))
+	assert.ErrorIs(t, errs[0], tree.ErrInconsistentParent)
+	assert.Equal(t, a, errs[0].Parent)
+	assert.ErrorIs(t, errs[1], tree.ErrAlreadyParented)
+	assert.Equal(t, b, errs[1].Parent)
+}
+
+func TestValidate_ReportsCycle(t *testing.T) {
+	a := tree.NewNode("a", nil)
+	root := tree.NewNode("root", &[]tree.NodeIFace{a})
+	a.AddChild(root)
+
+	errs := tree.Validate(root)
+	assert.Equal(t, 1, len(errs))
+	assert.ErrorIs(t, errs[0], tree.ErrCycle)
+	assert.Equal(t, root, errs[0].Node)
+	assert.Equal(t, "tree: operation would create a cycle: node root", errs[0].Error())
+}
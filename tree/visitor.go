@@ -25,13 +25,9 @@ func NewPreOrderVisitor() VisitorIFace {
 
 //Visit returns []NodeIFace in pre order
 func (v *PreOrderVisitor) Visit(n NodeIFace) interface{} {
-	nodes := make([]NodeIFace, 1)
-	nodes[0] = n
-	for _, child := range n.GetChildren() {
-		vNodes := child.Accept(v).([]NodeIFace)
-		nodes = append(nodes, vNodes...)
-	}
-	return nodes
+	return visitPreOrder(n, v, func(NodeIFace) bool {
+		return true
+	})
 }
 
 //PostOrderVisitor walks a tree in post-order
@@ -46,11 +42,22 @@ func NewPostOrderVisitor() VisitorIFace {
 //Visit returns []NodeIFace in post order
 func (v *PostOrderVisitor) Visit(n NodeIFace) interface{} {
 	nodes := make([]NodeIFace, 0)
-	for _, child := range n.GetChildren() {
-		vNodes := child.Accept(v).([]NodeIFace)
-		nodes = append(nodes, vNodes...)
+	stack := []walkFrame{{node: n}}
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		if children := stack[top].node.GetChildren(); stack[top].next < len(children) {
+			c := children[stack[top].next]
+			stack[top].next++
+			if walksChildren(c) {
+				stack = append(stack, walkFrame{node: c})
+			} else {
+				nodes = append(nodes, c.Accept(v).([]NodeIFace)...)
+			}
+			continue
+		}
+		nodes = append(nodes, stack[top].node)
+		stack = stack[:top]
 	}
-	nodes = append(nodes, n)
 	return nodes
 }
 
@@ -65,13 +72,97 @@ func NewLeafVisitor() VisitorIFace {
 
 //Visit returns []NodeIFace of leaves on the tree
 func (v *LeafVisitor) Visit(n NodeIFace) interface{} {
-	if n.IsLeaf() {
-		return []NodeIFace{n}
+	return visitPreOrder(n, v, func(node NodeIFace) bool {
+		return node.IsLeaf()
+	})
+}
+
+//LevelOrderVisitor walks a tree in level-order (breadth first)
+type LevelOrderVisitor struct {
+	VisitorIFace
+}
+
+func NewLevelOrderVisitor() VisitorIFace {
+	return new(LevelOrderVisitor)
+}
+
+//Visit returns []NodeIFace in level order
+func (v *LevelOrderVisitor) Visit(n NodeIFace) interface{} {
+	nodes := []NodeIFace{n}
+	for i := 0; i < len(nodes); i++ {
+		nodes = append(nodes, nodes[i].GetChildren()...)
 	}
-	nodes := make([]NodeIFace, 0)
-	for _, child := range n.GetChildren() {
-		vNodes := child.Accept(v).([]NodeIFace)
-		nodes = append(nodes, vNodes...)
+	return nodes
+}
+
+//LevelsVisitor groups the nodes of a tree by their depth below the visited node
+type LevelsVisitor struct {
+	VisitorIFace
+}
+
+func NewLevelsVisitor() VisitorIFace {
+	return new(LevelsVisitor)
+}
+
+//Visit returns [][]NodeIFace, one []NodeIFace per level, each in left to right order
+func (v *LevelsVisitor) Visit(n NodeIFace) interface{} {
+	levels := make([][]NodeIFace, 0)
+	for level := []NodeIFace{n}; len(level) > 0; {
+		levels = append(levels, level)
+		next := make([]NodeIFace, 0)
+		for _, node := range level {
+			next = append(next, node.GetChildren()...)
+		}
+		level = next
+	}
+	return levels
+}
+
+//ZigZagVisitor groups the nodes of a tree by level, alternating the direction of each level
+type ZigZagVisitor struct {
+	VisitorIFace
+}
+
+func NewZigZagVisitor() VisitorIFace {
+	return new(ZigZagVisitor)
+}
+
+//Visit returns [][]NodeIFace, one []NodeIFace per level, with even levels in left to right order
+//and odd levels in right to left order
+func (v *ZigZagVisitor) Visit(n NodeIFace) interface{} {
+	levels := n.Accept(NewLevelsVisitor()).([][]NodeIFace)
+	for i := 1; i < len(levels); i += 2 {
+		level := levels[i]
+		for l, r := 0, len(level)-1; l < r; l, r = l+1, r-1 {
+			level[l], level[r] = level[r], level[l]
+		}
+	}
+	return levels
+}
+
+//walksChildren reports whether the visitors walk the children of n themselves. Other implementations of NodeIFace
+//are handed the visitor through their Accept method, as they may visit their children in their own way
+func walksChildren(n NodeIFace) bool {
+	switch n.(type) {
+	case *Node, *PersistentNode:
+		return true
 	}
+	return false
+}
+
+//visitPreOrder returns the nodes of the tree rooted at n, in pre-order, for which keep returns true. The subtree of
+//a node that walksChildren does not report is visited by calling its Accept method with v
+func visitPreOrder(n NodeIFace, v VisitorIFace, keep func(NodeIFace) bool) []NodeIFace {
+	nodes := make([]NodeIFace, 0)
+	Walk(n, func(node NodeIFace, depth int) WalkAction {
+		if depth > 0 && !walksChildren(node) {
+			nodes = append(nodes, node.Accept(v).([]NodeIFace)...)
+			return WalkSkipChildren
+		}
+		if keep(node) {
+			nodes = append(nodes, node)
+		}
+		return WalkContinue
+	})
 	return nodes
 }
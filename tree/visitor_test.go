@@ -6,6 +6,18 @@ import (
 	"testing"
 )
 
+//buildChain returns the root and the leaf of a linked-list shaped tree with size nodes
+func buildChain(size int) (root, leaf tree.NodeIFace) {
+	root = tree.NewNode(0, nil)
+	leaf = root
+	for i := 1; i < size; i++ {
+		child := tree.NewNode(i, nil)
+		leaf.AddChild(child)
+		leaf = child
+	}
+	return root, leaf
+}
+
 func TestPreOrderVisitor_ImplementsVisitorInterface(t *testing.T) {
 	sut := tree.NewPreOrderVisitor()
 	_, ok := sut.(tree.VisitorIFace)
@@ -177,3 +189,213 @@ func TestLeafVisitor_TheYieldOfALeafNodeIsTheNodeItself(t *testing.T) {
 	actual := root.Accept(sut)
 	assert.Equal(t, expected, actual)
 }
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestLevelOrderVisitor_WalkTree(t *testing.T) {
+	root := tree.NewNode("root", nil)
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	d := tree.NewNode("d", nil)
+	e := tree.NewNode("e", nil)
+	f := tree.NewNode("f", nil)
+	root = root.AddChild(a).AddChild(b).AddChild(c)
+	a = a.AddChild(d).AddChild(e)
+	b = b.AddChild(f)
+	sut := tree.NewLevelOrderVisitor()
+	expected := []tree.NodeIFace{root, a, b, c, d, e, f}
+	assert.Equal(t, expected, root.Accept(sut))
+	assert.Equal(t, []tree.NodeIFace{a, d, e}, a.Accept(sut))
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestLevelsVisitor_GroupsNodesByLevel(t *testing.T) {
+	root := tree.NewNode("root", nil)
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	d := tree.NewNode("d", nil)
+	e := tree.NewNode("e", nil)
+	f := tree.NewNode("f", nil)
+	root = root.AddChild(a).AddChild(b).AddChild(c)
+	a = a.AddChild(d).AddChild(e)
+	b = b.AddChild(f)
+	sut := tree.NewLevelsVisitor()
+	expected := [][]tree.NodeIFace{{root}, {a, b, c}, {d, e, f}}
+	assert.Equal(t, expected, root.Accept(sut))
+	assert.Equal(t, [][]tree.NodeIFace{{c}}, c.Accept(sut))
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ * |
+ * g
+ */
+func TestZigZagVisitor_AlternatesLevelDirection(t *testing.T) {
+	root := tree.NewNode("root", nil)
+	a := tree.NewNode("a", nil)
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	d := tree.NewNode("d", nil)
+	e := tree.NewNode("e", nil)
+	f := tree.NewNode("f", nil)
+	g := tree.NewNode("g", nil)
+	root = root.AddChild(a).AddChild(b).AddChild(c)
+	a = a.AddChild(d).AddChild(e)
+	b = b.AddChild(f)
+	d = d.AddChild(g)
+	sut := tree.NewZigZagVisitor()
+	expected := [][]tree.NodeIFace{{root}, {c, b, a}, {d, e, f}, {g}}
+	assert.Equal(t, expected, root.Accept(sut))
+	//the tree itself is unchanged
+	assert.Equal(t, []tree.NodeIFace{a, b, c}, root.GetChildren())
+}
+
+func TestVisitors_WalkVeryDeepTree(t *testing.T) {
+	const size = 100000
+	root, leaf := buildChain(size)
+	assert.Equal(t, size, len(root.Accept(tree.NewPreOrderVisitor()).([]tree.NodeIFace)))
+	post := root.Accept(tree.NewPostOrderVisitor()).([]tree.NodeIFace)
+	assert.Equal(t, size, len(post))
+	assert.Equal(t, leaf, post[0])
+	assert.Equal(t, []tree.NodeIFace{leaf}, root.Accept(tree.NewLeafVisitor()))
+	assert.Equal(t, size-1, root.GetHeight())
+	assert.Equal(t, size, root.GetSize())
+	assert.Equal(t, size-1, leaf.GetDepth())
+	assert.Equal(t, size-1, len(leaf.GetAncestors()))
+	assert.Nil(t, tree.Validate(root))
+}
+
+//closedNode is a node that hides its children from visitors
+type closedNode struct {
+	tree.NodeIFace
+}
+
+func (n *closedNode) SetParent(p tree.NodeIFace) tree.NodeIFace {
+	n.NodeIFace.SetParent(p)
+	return n
+}
+
+func (n *closedNode) Accept(tree.VisitorIFace) interface{} {
+	return []tree.NodeIFace{n}
+}
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func TestVisitors_CallAcceptOfOtherNodeTypes(t *testing.T) {
+	root := tree.NewNode("root", nil)
+	a := &closedNode{tree.NewNode("a", nil)}
+	b := tree.NewNode("b", nil)
+	c := tree.NewNode("c", nil)
+	root.AddChild(a).AddChild(b).AddChild(c)
+	a.AddChild(tree.NewNode("d", nil)).AddChild(tree.NewNode("e", nil))
+	b.AddChild(tree.NewNode("f", nil))
+	f := b.GetChildren()[0]
+
+	assert.Equal(t, []tree.NodeIFace{root, a, b, f, c}, root.Accept(tree.NewPreOrderVisitor()))
+	assert.Equal(t, []tree.NodeIFace{a, f, b, c, root}, root.Accept(tree.NewPostOrderVisitor()))
+	assert.Equal(t, []tree.NodeIFace{a, f, c}, root.Accept(tree.NewLeafVisitor()))
+	assert.Equal(t, []tree.NodeIFace{root, a, b, f, c}, root.Accept(tree.NewFilterVisitor(func(n tree.NodeIFace) bool {
+		return n.GetValue() != "a"
+	})), "the filter is not used for the nodes returned by Accept")
+}
+
+const benchmarkChainSize = 1000000
+
+var benchmarkChainRoot, benchmarkChainLeaf tree.NodeIFace
+
+//benchmarkChain returns a shared 1M node linked-list shaped tree
+func benchmarkChain(b *testing.B) (root, leaf tree.NodeIFace) {
+	if benchmarkChainRoot == nil {
+		benchmarkChainRoot, benchmarkChainLeaf = buildChain(benchmarkChainSize)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	return benchmarkChainRoot, benchmarkChainLeaf
+}
+
+func BenchmarkPreOrderVisitor_Chain(b *testing.B) {
+	root, _ := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		root.Accept(tree.NewPreOrderVisitor())
+	}
+}
+
+func BenchmarkPostOrderVisitor_Chain(b *testing.B) {
+	root, _ := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		root.Accept(tree.NewPostOrderVisitor())
+	}
+}
+
+func BenchmarkLeafVisitor_Chain(b *testing.B) {
+	root, _ := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		root.Accept(tree.NewLeafVisitor())
+	}
+}
+
+func BenchmarkLevelOrderVisitor_Chain(b *testing.B) {
+	root, _ := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		root.Accept(tree.NewLevelOrderVisitor())
+	}
+}
+
+func BenchmarkWalk_Chain(b *testing.B) {
+	root, _ := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		tree.Walk(root, func(tree.NodeIFace, int) tree.WalkAction {
+			return tree.WalkContinue
+		})
+	}
+}
+
+func BenchmarkNode_GetHeight_Chain(b *testing.B) {
+	root, _ := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		root.GetHeight()
+	}
+}
+
+func BenchmarkNode_GetSize_Chain(b *testing.B) {
+	root, _ := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		root.GetSize()
+	}
+}
+
+func BenchmarkNode_GetDepth_Chain(b *testing.B) {
+	_, leaf := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		leaf.GetDepth()
+	}
+}
+
+func BenchmarkNode_GetAncestors_Chain(b *testing.B) {
+	_, leaf := benchmarkChain(b)
+	for i := 0; i < b.N; i++ {
+		leaf.GetAncestors()
+	}
+}
@@ -0,0 +1,75 @@
+package tree
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+//WalkAction tells a walk how to carry on after a node has been visited
+type WalkAction int
+
+const (
+	//WalkContinue carries on walking the tree
+	WalkContinue WalkAction = iota
+	//WalkSkipChildren carries on walking the tree, but not the children of the current node
+	WalkSkipChildren
+	//WalkStop ends the walk
+	WalkStop
+)
+
+//WalkFunc is called for each node of a walk with the depth of the node below the walked root
+type WalkFunc func(n NodeIFace, depth int) WalkAction
+
+//walkFrame is an entry on the explicit stack used to walk a tree
+type walkFrame struct {
+	node  NodeIFace
+	depth int
+	//next is the index of the next child to walk, for walks that return to a node after its children
+	next int
+}
+
+//Walk calls fn for each node of the tree rooted at root in pre-order, the same order as
+//PreOrderVisitor, without collecting the nodes. The walk ends as soon as fn returns WalkStop.
+//The walk uses an explicit stack, so it is safe for trees of any depth.
+func Walk(root NodeIFace, fn WalkFunc) {
+	stack := []walkFrame{{node: root}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch fn(f.node, f.depth) {
+		case WalkStop:
+			return
+		case WalkSkipChildren:
+			continue
+		}
+		children := f.node.GetChildren()
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, walkFrame{node: children[i], depth: f.depth + 1})
+		}
+	}
+}
+
+//WalkPostOrder calls fn for each node of the tree rooted at root in post-order, the same order as
+//PostOrderVisitor, without collecting the nodes. The walk ends as soon as fn returns WalkStop.
+//As children are visited before their parent, WalkSkipChildren is the same as WalkContinue.
+//The walk uses an explicit stack, so it is safe for trees of any depth.
+func WalkPostOrder(root NodeIFace, fn WalkFunc) {
+	stack := []walkFrame{{node: root}}
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		if children := stack[top].node.GetChildren(); stack[top].next < len(children) {
+			c := children[stack[top].next]
+			stack[top].next++
+			stack = append(stack, walkFrame{node: c, depth: stack[top].depth + 1})
+			continue
+		}
+		f := stack[top]
+		stack = stack[:top]
+		if fn(f.node, f.depth) == WalkStop {
+			return
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package tree_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func buildWalkTree() (root, a, b, c, d, e, f tree.NodeIFace) {
+	root = tree.NewNode("root", nil)
+	a = tree.NewNode("a", nil)
+	b = tree.NewNode("b", nil)
+	c = tree.NewNode("c", nil)
+	d = tree.NewNode("d", nil)
+	e = tree.NewNode("e", nil)
+	f = tree.NewNode("f", nil)
+	root.AddChild(a).AddChild(b).AddChild(c)
+	a.AddChild(d).AddChild(e)
+	b.AddChild(f)
+	return
+}
+
+func TestWalk_VisitsNodesInPreOrderWithDepth(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	nodes := make([]tree.NodeIFace, 0)
+	depths := make([]int, 0)
+	tree.Walk(root, func(n tree.NodeIFace, depth int) tree.WalkAction {
+		nodes = append(nodes, n)
+		depths = append(depths, depth)
+		return tree.WalkContinue
+	})
+	assert.Equal(t, root.Accept(tree.NewPreOrderVisitor()), nodes)
+	assert.Equal(t, []int{0, 1, 2, 2, 1, 2, 1}, depths)
+}
+
+func TestWalk_SkipChildren(t *testing.T) {
+	root, a, b, c, _, _, f := buildWalkTree()
+	nodes := make([]tree.NodeIFace, 0)
+	tree.Walk(root, func(n tree.NodeIFace, depth int) tree.WalkAction {
+		nodes = append(nodes, n)
+		if n == a {
+			return tree.WalkSkipChildren
+		}
+		return tree.WalkContinue
+	})
+	assert.Equal(t, []tree.NodeIFace{root, a, b, f, c}, nodes)
+}
+
+func TestWalk_Stop(t *testing.T) {
+	root, a, _, _, d, e, _ := buildWalkTree()
+	nodes := make([]tree.NodeIFace, 0)
+	tree.Walk(root, func(n tree.NodeIFace, depth int) tree.WalkAction {
+		nodes = append(nodes, n)
+		if n == e {
+			return tree.WalkStop
+		}
+		return tree.WalkContinue
+	})
+	assert.Equal(t, []tree.NodeIFace{root, a, d, e}, nodes)
+}
+
+func TestWalkPostOrder_VisitsNodesInPostOrderWithDepth(t *testing.T) {
+	root, _, _, _, _, _, _ := buildWalkTree()
+	nodes := make([]tree.NodeIFace, 0)
+	depths := make([]int, 0)
+	tree.WalkPostOrder(root, func(n tree.NodeIFace, depth int) tree.WalkAction {
+		nodes = append(nodes, n)
+		depths = append(depths, depth)
+		return tree.WalkSkipChildren
+	})
+	assert.Equal(t, root.Accept(tree.NewPostOrderVisitor()), nodes)
+	assert.Equal(t, []int{2, 2, 1, 2, 1, 1, 0}, depths)
+}
+
+func TestWalkPostOrder_Stop(t *testing.T) {
+	root, a, _, _, d, e, f := buildWalkTree()
+	nodes := make([]tree.NodeIFace, 0)
+	tree.WalkPostOrder(root, func(n tree.NodeIFace, depth int) tree.WalkAction {
+		nodes = append(nodes, n)
+		if n == f {
+			return tree.WalkStop
+		}
+		return tree.WalkContinue
+	})
+	assert.Equal(t, []tree.NodeIFace{d, e, a, f}, nodes)
+}
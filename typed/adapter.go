@@ -0,0 +1,62 @@
+package typed
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Generic Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/chippyash/go-hierarchy-tree/tree"
+)
+
+//ErrValueType is returned when an untyped node value cannot be converted to the required type
+var ErrValueType = errors.New("typed: node value is not of the required type")
+
+//CopyToUntyped copies the tree rooted at n into a new untyped tree.NodeIFace tree. The copy is independent,
+//so changes made to one tree are not seen in the other. Values are copied as is, so a tree of pointers will
+//share the pointed to values.
+func CopyToUntyped[T any](n NodeIFace[T]) tree.NodeIFace {
+	node := tree.NewNode(n.GetValue(), nil)
+	for _, c := range n.GetChildren() {
+		node.AddChild(CopyToUntyped(c))
+	}
+	return node
+}
+
+//CopyFromUntyped copies the untyped tree rooted at n into a new, independent, type safe tree.
+//A nil value is converted to the zero value of T. Any other value that is not a T
+//results in an ErrValueType error.
+func CopyFromUntyped[T any](n tree.NodeIFace) (NodeIFace[T], error) {
+	v, err := typedValue[T](n.GetValue())
+	if err != nil {
+		return nil, err
+	}
+	node := NewNode(v, nil)
+	for _, c := range n.GetChildren() {
+		child, err := CopyFromUntyped[T](c)
+		if err != nil {
+			return nil, err
+		}
+		node.AddChild(child)
+	}
+	return node, nil
+}
+
+func typedValue[T any](v interface{}) (T, error) {
+	var zero T
+	if v == nil {
+		return zero, nil
+	}
+	tv, ok := v.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: got %T, want %v", ErrValueType, v, reflect.TypeOf((*T)(nil)).Elem())
+	}
+	return tv, nil
+}
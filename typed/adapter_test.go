@@ -0,0 +1,55 @@
+package typed_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/tree"
+	"github.com/chippyash/go-hierarchy-tree/typed"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCopyToUntyped(t *testing.T) {
+	root, _, _, _, _, _, _ := buildTree()
+	untyped := typed.CopyToUntyped(root)
+	values := make([]interface{}, 0)
+	for _, n := range untyped.Accept(tree.NewPreOrderVisitor()).([]tree.NodeIFace) {
+		values = append(values, n.GetValue())
+	}
+	assert.Equal(t, []interface{}{"root", "a", "d", "e", "b", "f", "c"}, values)
+}
+
+func TestCopyFromUntyped(t *testing.T) {
+	child := tree.NewNode(account{"bank", 10}, nil)
+	root := tree.NewNode(account{"assets", 0}, &[]tree.NodeIFace{child})
+	sut, err := typed.CopyFromUntyped[account](root)
+	assert.NoError(t, err)
+	assert.Equal(t, "assets", sut.GetValue().name)
+	assert.Equal(t, 10, sut.GetChildren()[0].GetValue().balance)
+	assert.Equal(t, sut, sut.GetChildren()[0].GetParent())
+}
+
+func TestCopyFromUntyped_NilValueBecomesZeroValue(t *testing.T) {
+	sut, err := typed.CopyFromUntyped[*account](tree.NewNode(nil, nil))
+	assert.NoError(t, err)
+	assert.Nil(t, sut.GetValue())
+}
+
+func TestCopyFromUntyped_WrongValueTypeReturnsError(t *testing.T) {
+	child := tree.NewNode("not an account", nil)
+	root := tree.NewNode(account{"assets", 0}, &[]tree.NodeIFace{child})
+	_, err := typed.CopyFromUntyped[account](root)
+	assert.ErrorIs(t, err, typed.ErrValueType)
+}
+
+func TestCopyToUntyped_IsIndependent(t *testing.T) {
+	root, a, _, _, _, _, _ := buildTree()
+	untyped := typed.CopyToUntyped(root)
+	untyped.GetChildAt(0).SetValue("A")
+	untyped.AddChild(tree.NewNode("x", nil))
+	assert.Equal(t, "a", a.GetValue())
+	assert.Len(t, root.GetChildren(), 3)
+
+	sut, err := typed.CopyFromUntyped[string](untyped)
+	assert.NoError(t, err)
+	sut.SetValue("ROOT")
+	assert.Equal(t, "root", untyped.GetValue())
+}
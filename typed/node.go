@@ -0,0 +1,203 @@
+package typed
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Generic Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+//NodeIFace an interface for a type safe Tree Node whose value is of type T
+type NodeIFace[T any] interface {
+	//SetValue sets the value of this node and returns this node
+	SetValue(v T) NodeIFace[T]
+	//GetValue returns the value of this node
+	GetValue() T
+	//AddChild adds a child to the set of children for this node and returns this node
+	AddChild(NodeIFace[T]) NodeIFace[T]
+	//RemoveChild removes the child node matching the input parameter and returns this node
+	RemoveChild(NodeIFace[T]) NodeIFace[T]
+	//RemoveAllChildren removes all child nodes of this node (and their children) and returns this node
+	RemoveAllChildren() NodeIFace[T]
+	//GetChildren returns all child nodes of this node
+	GetChildren() []NodeIFace[T]
+	//SetChildren replaces all child nodes of this node with new ones and returns this node
+	SetChildren(...NodeIFace[T]) NodeIFace[T]
+	//SetParent sets the parent of this node and returns this node
+	SetParent(NodeIFace[T]) NodeIFace[T]
+	//GetParent returns the parent node of this node or nil if none
+	GetParent() NodeIFace[T]
+	//GetAncestors retrieves all ancestors of node excluding current node
+	GetAncestors() []NodeIFace[T]
+	//GetAncestorsAndSelf retrieves all ancestors of node as well as the node itself
+	GetAncestorsAndSelf() []NodeIFace[T]
+	//GetSiblings retrieves all neighboring nodes (children of same parent), excluding the current node
+	GetSiblings() []NodeIFace[T]
+	//GetSiblingsAndSelf returns all neighboring nodes (children of same parent), including the current node
+	GetSiblingsAndSelf() []NodeIFace[T]
+	//IsRoot returns true if the node is the root, false otherwise
+	IsRoot() bool
+	//IsChild returns true if the node is a child, false otherwise
+	IsChild() bool
+	//IsLeaf  returns true if the node is a leaf node, false otherwise
+	IsLeaf() bool
+	//GetDepth returns the distance from the current node to the root
+	GetDepth() int
+	//GetHeight returns the height of the tree whose root is this node
+	GetHeight() int
+	//GetSize returns the number of nodes in the tree rooted at this node
+	GetSize() int
+	//Accept Accept method for the visitor pattern (see http://en.wikipedia.org/wiki/Visitor_pattern)
+	Accept(v VisitorIFace[T]) []NodeIFace[T]
+}
+
+//Node is a type safe Tree Node
+type Node[T any] struct {
+	NodeIFace[T]
+	value    T
+	children []NodeIFace[T]
+	parent   NodeIFace[T]
+}
+
+//NewNode returns a new Node
+func NewNode[T any](v T, children *[]NodeIFace[T]) NodeIFace[T] {
+	n := &Node[T]{
+		children: make([]NodeIFace[T], 0),
+	}
+	if children != nil {
+		return n.SetValue(v).SetChildren(*children...)
+	}
+
+	return n.SetValue(v)
+}
+
+func (n *Node[T]) SetValue(v T) NodeIFace[T] {
+	n.value = v
+	return n
+}
+
+func (n *Node[T]) GetValue() T {
+	return n.value
+}
+
+func (n *Node[T]) AddChild(c NodeIFace[T]) NodeIFace[T] {
+	c = c.SetParent(n)
+	n.children = append(n.children, c)
+	return n
+}
+
+func (n *Node[T]) RemoveChild(c NodeIFace[T]) NodeIFace[T] {
+	for i, ch := range n.children {
+		if c == ch {
+			n.children = append(n.children[:i], n.children[i+1:]...)
+			c.SetParent(nil)
+			break
+		}
+	}
+	return n
+}
+
+func (n *Node[T]) RemoveAllChildren() NodeIFace[T] {
+	for _, c := range n.children {
+		c.SetParent(nil)
+	}
+	n.children = make([]NodeIFace[T], 0)
+	return n
+}
+
+func (n *Node[T]) GetChildren() []NodeIFace[T] {
+	return n.children
+}
+
+func (n *Node[T]) SetChildren(c ...NodeIFace[T]) NodeIFace[T] {
+	n.RemoveAllChildren()
+	for _, cc := range c {
+		n.AddChild(cc)
+	}
+	return n
+}
+
+func (n *Node[T]) SetParent(p NodeIFace[T]) NodeIFace[T] {
+	n.parent = p
+	return n
+}
+
+func (n *Node[T]) GetParent() NodeIFace[T] {
+	return n.parent
+}
+
+func (n *Node[T]) GetAncestors() []NodeIFace[T] {
+	parents := make([]NodeIFace[T], 0)
+	for p := n.GetParent(); p != nil; p = p.GetParent() {
+		parents = append(parents, p)
+	}
+	//reverse so that the root comes first
+	for i, j := 0, len(parents)-1; i < j; i, j = i+1, j-1 {
+		parents[i], parents[j] = parents[j], parents[i]
+	}
+	return parents
+}
+
+func (n *Node[T]) GetAncestorsAndSelf() []NodeIFace[T] {
+	return append(n.GetAncestors(), n)
+}
+
+func (n *Node[T]) GetSiblings() []NodeIFace[T] {
+	siblings := make([]NodeIFace[T], 0)
+	for _, v := range n.GetSiblingsAndSelf() {
+		if v != NodeIFace[T](n) {
+			siblings = append(siblings, v)
+		}
+	}
+	return siblings
+}
+
+func (n *Node[T]) GetSiblingsAndSelf() []NodeIFace[T] {
+	if n.IsRoot() {
+		return []NodeIFace[T]{n}
+	}
+	return n.GetParent().GetChildren()
+}
+
+func (n *Node[T]) IsRoot() bool {
+	return n.parent == nil
+}
+
+func (n *Node[T]) IsChild() bool {
+	return n.parent != nil
+}
+
+func (n *Node[T]) IsLeaf() bool {
+	return len(n.children) == 0
+}
+
+func (n *Node[T]) GetDepth() int {
+	depth := 0
+	for p := n.GetParent(); p != nil; p = p.GetParent() {
+		depth++
+	}
+	return depth
+}
+
+func (n *Node[T]) GetHeight() int {
+	height := 0
+	preOrder[T](n, func(_ NodeIFace[T], depth int) {
+		if depth > height {
+			height = depth
+		}
+	})
+	return height
+}
+
+func (n *Node[T]) GetSize() int {
+	size := 0
+	preOrder[T](n, func(NodeIFace[T], int) {
+		size++
+	})
+	return size
+}
+
+func (n *Node[T]) Accept(v VisitorIFace[T]) []NodeIFace[T] {
+	return v.Visit(n)
+}
@@ -0,0 +1,113 @@
+package typed_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/typed"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type account struct {
+	name    string
+	balance int
+}
+
+func TestNode_NewNode(t *testing.T) {
+	node := typed.NewNode[string]("", nil)
+	//type assertion
+	assert.IsType(t, typed.Node[string]{}, *node.(*typed.Node[string]))
+	//interface assertion
+	_, ok := node.(typed.NodeIFace[string])
+	assert.True(t, ok)
+}
+
+func TestNode_NewNodeWithChildren(t *testing.T) {
+	children := []typed.NodeIFace[int]{typed.NewNode(1, nil), typed.NewNode(2, nil)}
+	node := typed.NewNode(0, &children)
+	assert.Equal(t, 0, node.GetValue())
+	assert.Equal(t, 2, len(node.GetChildren()))
+}
+
+func TestNode_SetAndGetValueIsTypeSafe(t *testing.T) {
+	node := typed.NewNode(account{"cash", 0}, nil).
+		SetValue(account{"bank", 100})
+	//no type assertion required
+	var acc account = node.GetValue()
+	assert.Equal(t, "bank", acc.name)
+	assert.Equal(t, 100, acc.balance)
+}
+
+func TestNode_SetAndGetChildren(t *testing.T) {
+	children := []typed.NodeIFace[int]{typed.NewNode(1, nil), typed.NewNode(2, nil)}
+	val := typed.NewNode(0, nil).
+		SetChildren(children...).
+		SetChildren(children...).
+		GetChildren()
+	assert.Equal(t, 2, len(val))
+	//check that parent nodes are set on children
+	assert.Equal(t, 0, val[0].GetParent().GetValue())
+	assert.Equal(t, 0, val[1].GetParent().GetValue())
+}
+
+func TestNode_RemoveChild(t *testing.T) {
+	children := []typed.NodeIFace[int]{typed.NewNode(1, nil), typed.NewNode(2, nil), typed.NewNode(3, nil)}
+	node := typed.NewNode(0, &children).RemoveChild(children[0])
+	assert.Equal(t, []typed.NodeIFace[int]{children[1], children[2]}, node.GetChildren())
+	assert.Nil(t, children[0].GetParent())
+}
+
+func TestNode_RemoveAllChildren(t *testing.T) {
+	children := []typed.NodeIFace[int]{typed.NewNode(1, nil), typed.NewNode(2, nil)}
+	node := typed.NewNode(0, &children).RemoveAllChildren()
+	assert.Equal(t, 0, len(node.GetChildren()))
+	assert.Nil(t, children[0].GetParent())
+	assert.Nil(t, children[1].GetParent())
+}
+
+func TestNode_GetAncestors(t *testing.T) {
+	child3 := typed.NewNode(3, nil)
+	child2 := typed.NewNode(2, &[]typed.NodeIFace[int]{child3})
+	child1 := typed.NewNode(1, &[]typed.NodeIFace[int]{child2})
+	root := typed.NewNode(0, &[]typed.NodeIFace[int]{child1})
+
+	assert.Equal(t, []typed.NodeIFace[int]{root, child1, child2}, child3.GetAncestors())
+	assert.Equal(t, []typed.NodeIFace[int]{root, child1, child2, child3}, child3.GetAncestorsAndSelf())
+}
+
+func TestNode_GetSiblings(t *testing.T) {
+	child1 := typed.NewNode(1, nil)
+	child2 := typed.NewNode(2, nil)
+	child3 := typed.NewNode(3, nil)
+	_ = typed.NewNode(0, &[]typed.NodeIFace[int]{child1, child2, child3})
+
+	assert.Equal(t, []typed.NodeIFace[int]{child1, child3}, child2.GetSiblings())
+	assert.Equal(t, []typed.NodeIFace[int]{child1, child2, child3}, child2.GetSiblingsAndSelf())
+}
+
+func TestNode_Tests(t *testing.T) {
+	root := typed.NewNode(0, nil)
+	child := typed.NewNode(1, nil)
+	assert.True(t, root.IsLeaf())
+	assert.False(t, child.IsChild())
+
+	root.AddChild(child)
+	assert.True(t, root.IsRoot())
+	assert.False(t, root.IsLeaf())
+	assert.False(t, child.IsRoot())
+	assert.True(t, child.IsChild())
+}
+
+func TestNode_Metrics(t *testing.T) {
+	child1 := typed.NewNode(1, nil)
+	child2 := typed.NewNode(2, nil)
+	child3 := typed.NewNode(3, nil)
+	child4 := typed.NewNode(4, nil)
+	root := typed.NewNode(0, &[]typed.NodeIFace[int]{child1, child2, child3})
+	child3.AddChild(child4)
+
+	assert.Equal(t, 0, root.GetDepth())
+	assert.Equal(t, 2, child4.GetDepth())
+	assert.Equal(t, 2, root.GetHeight())
+	assert.Equal(t, 0, child1.GetHeight())
+	assert.Equal(t, 5, root.GetSize())
+	assert.Equal(t, 2, child3.GetSize())
+}
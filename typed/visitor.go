@@ -0,0 +1,148 @@
+package typed
+
+/**
+ * Simple Double Entry Accounting V3 for Go
+ * Generic Tree implementation
+ * @author Ashley Kitson
+ * @copyright Ashley Kitson, 2022, UK
+ * @license BSD-3-Clause See LICENSE.md
+ */
+
+//VisitorIFace Visitor interface for type safe Nodes
+type VisitorIFace[T any] interface {
+	//Visit visits each node starting at given node
+	Visit(NodeIFace[T]) []NodeIFace[T]
+}
+
+//PreOrderVisitor walks a tree in pre-order
+type PreOrderVisitor[T any] struct {
+	VisitorIFace[T]
+}
+
+func NewPreOrderVisitor[T any]() VisitorIFace[T] {
+	return new(PreOrderVisitor[T])
+}
+
+//Visit returns []NodeIFace[T] in pre order
+func (v *PreOrderVisitor[T]) Visit(n NodeIFace[T]) []NodeIFace[T] {
+	return visitPreOrder[T](n, v, func(NodeIFace[T]) bool {
+		return true
+	})
+}
+
+//PostOrderVisitor walks a tree in post-order
+type PostOrderVisitor[T any] struct {
+	VisitorIFace[T]
+}
+
+func NewPostOrderVisitor[T any]() VisitorIFace[T] {
+	return new(PostOrderVisitor[T])
+}
+
+//Visit returns []NodeIFace[T] in post order
+func (v *PostOrderVisitor[T]) Visit(n NodeIFace[T]) []NodeIFace[T] {
+	nodes := make([]NodeIFace[T], 0)
+	stack := []frame[T]{{node: n}}
+	for len(stack) > 0 {
+		top := len(stack) - 1
+		if children := stack[top].node.GetChildren(); stack[top].next < len(children) {
+			c := children[stack[top].next]
+			stack[top].next++
+			if walksChildren(c) {
+				stack = append(stack, frame[T]{node: c})
+			} else {
+				nodes = append(nodes, c.Accept(v)...)
+			}
+			continue
+		}
+		nodes = append(nodes, stack[top].node)
+		stack = stack[:top]
+	}
+	return nodes
+}
+
+//LeafVisitor returns the leaves of a tree
+type LeafVisitor[T any] struct {
+	VisitorIFace[T]
+}
+
+func NewLeafVisitor[T any]() VisitorIFace[T] {
+	return new(LeafVisitor[T])
+}
+
+//Visit returns []NodeIFace[T] of leaves on the tree
+func (v *LeafVisitor[T]) Visit(n NodeIFace[T]) []NodeIFace[T] {
+	return visitPreOrder[T](n, v, func(node NodeIFace[T]) bool {
+		return node.IsLeaf()
+	})
+}
+
+//FilterFunc signature for filter functions
+type FilterFunc[T any] func(NodeIFace[T]) bool
+
+//FilterVisitor is a visitor that filters nodes
+type FilterVisitor[T any] struct {
+	VisitorIFace[T]
+	filter FilterFunc[T]
+}
+
+//NewFilterVisitor returns a FilterVisitor
+func NewFilterVisitor[T any](filter FilterFunc[T]) VisitorIFace[T] {
+	return &FilterVisitor[T]{filter: filter}
+}
+
+//Visit filters nodes (pre-order traversal) that match the filter and returns []NodeIFace[T]
+func (v *FilterVisitor[T]) Visit(n NodeIFace[T]) []NodeIFace[T] {
+	return visitPreOrder[T](n, v, v.filter)
+}
+
+//walksChildren reports whether the visitors walk the children of n themselves. Other implementations of NodeIFace[T]
+//are handed the visitor through their Accept method, as they may visit their children in their own way
+func walksChildren[T any](n NodeIFace[T]) bool {
+	_, ok := n.(*Node[T])
+	return ok
+}
+
+//visitPreOrder returns the nodes of the tree rooted at n, in pre-order, for which keep returns true. The subtree of
+//a node that walksChildren does not report is visited by calling its Accept method with v
+func visitPreOrder[T any](n NodeIFace[T], v VisitorIFace[T], keep func(NodeIFace[T]) bool) []NodeIFace[T] {
+	nodes := make([]NodeIFace[T], 0)
+	stack := []frame[T]{{node: n}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if f.depth > 0 && !walksChildren(f.node) {
+			nodes = append(nodes, f.node.Accept(v)...)
+			continue
+		}
+		if keep(f.node) {
+			nodes = append(nodes, f.node)
+		}
+		children := f.node.GetChildren()
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, frame[T]{node: children[i], depth: f.depth + 1})
+		}
+	}
+	return nodes
+}
+
+//frame is an entry on the explicit stack used to walk a tree
+type frame[T any] struct {
+	node  NodeIFace[T]
+	depth int
+	next  int
+}
+
+//preOrder calls fn for each node of the tree rooted at n in pre-order, using an explicit stack
+func preOrder[T any](n NodeIFace[T], fn func(node NodeIFace[T], depth int)) {
+	stack := []frame[T]{{node: n}}
+	for len(stack) > 0 {
+		f := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		fn(f.node, f.depth)
+		children := f.node.GetChildren()
+		for i := len(children) - 1; i >= 0; i-- {
+			stack = append(stack, frame[T]{node: children[i], depth: f.depth + 1})
+		}
+	}
+}
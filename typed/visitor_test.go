@@ -0,0 +1,81 @@
+package typed_test
+
+import (
+	"github.com/chippyash/go-hierarchy-tree/typed"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+/**
+ *    root
+ *    /|\
+ *   a b c
+ *  /| |
+ * d e f
+ */
+func buildTree() (root, a, b, c, d, e, f typed.NodeIFace[string]) {
+	root = typed.NewNode("root", nil)
+	a = typed.NewNode("a", nil)
+	b = typed.NewNode("b", nil)
+	c = typed.NewNode("c", nil)
+	d = typed.NewNode("d", nil)
+	e = typed.NewNode("e", nil)
+	f = typed.NewNode("f", nil)
+	root.AddChild(a).AddChild(b).AddChild(c)
+	a.AddChild(d).AddChild(e)
+	b.AddChild(f)
+	return
+}
+
+func TestPreOrderVisitor_WalkTree(t *testing.T) {
+	root, a, b, c, d, e, f := buildTree()
+	expected := []typed.NodeIFace[string]{root, a, d, e, b, f, c}
+	assert.Equal(t, expected, root.Accept(typed.NewPreOrderVisitor[string]()))
+}
+
+func TestPostOrderVisitor_WalkTree(t *testing.T) {
+	root, a, b, c, d, e, f := buildTree()
+	expected := []typed.NodeIFace[string]{d, e, a, f, b, c, root}
+	assert.Equal(t, expected, root.Accept(typed.NewPostOrderVisitor[string]()))
+}
+
+func TestLeafVisitor_GetLeaves(t *testing.T) {
+	root, _, _, c, d, e, f := buildTree()
+	expected := []typed.NodeIFace[string]{d, e, f, c}
+	assert.Equal(t, expected, root.Accept(typed.NewLeafVisitor[string]()))
+}
+
+func TestFilterVisitor_FilterTree(t *testing.T) {
+	root, _, b, _, _, e, _ := buildTree()
+	sut := typed.NewFilterVisitor(func(n typed.NodeIFace[string]) bool {
+		return n.GetValue() == "e" || n.GetValue() == "b"
+	})
+	expected := []typed.NodeIFace[string]{e, b}
+	assert.Equal(t, expected, root.Accept(sut))
+}
+
+//closedNode is a node that hides its children from visitors
+type closedNode struct {
+	typed.NodeIFace[string]
+}
+
+func (n *closedNode) SetParent(p typed.NodeIFace[string]) typed.NodeIFace[string] {
+	n.NodeIFace.SetParent(p)
+	return n
+}
+
+func (n *closedNode) Accept(typed.VisitorIFace[string]) []typed.NodeIFace[string] {
+	return []typed.NodeIFace[string]{n}
+}
+
+func TestVisitors_CallAcceptOfOtherNodeTypes(t *testing.T) {
+	root := typed.NewNode("root", nil)
+	a := &closedNode{typed.NewNode("a", nil)}
+	b := typed.NewNode("b", nil)
+	root.AddChild(a).AddChild(b)
+	a.AddChild(typed.NewNode("c", nil))
+
+	assert.Equal(t, []typed.NodeIFace[string]{root, a, b}, root.Accept(typed.NewPreOrderVisitor[string]()))
+	assert.Equal(t, []typed.NodeIFace[string]{a, b, root}, root.Accept(typed.NewPostOrderVisitor[string]()))
+	assert.Equal(t, []typed.NodeIFace[string]{a, b}, root.Accept(typed.NewLeafVisitor[string]()))
+}